@@ -8,6 +8,29 @@ func Avg(col tableColumn) aggregateFunc {
 	return aggregateFunc{col, avg}
 }
 
+// Count returns an Expression representing a call to the COUNT aggregate
+// function with the table column col as an argument.
+func Count(col tableColumn) aggregateFunc {
+	return aggregateFunc{col, count}
+}
+
+// CountAll returns an Expression representing COUNT(*), which counts all
+// rows. Since it doesn't refer to any table, it is usually combined with
+// an explicit call to the From method of a SelectQuery.
+func CountAll() countAll {
+	return countAll{}
+}
+
+type countAll struct{}
+
+func (countAll) ToSQLExpr(*Params) string {
+	return "COUNT(*)"
+}
+
+func (countAll) Relations() []string {
+	return nil
+}
+
 // Max returns an Expression representing a call to the MAX aggregate
 // function with the table column col as an argument.
 func Max(col tableColumn) aggregateFunc {
@@ -32,7 +55,8 @@ type aggregateFunc struct {
 }
 
 func (f aggregateFunc) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("%s(%s)", f.fnType, f.column.ToSQLExpr(p))
+	p.check(f.fnType)
+	return fmt.Sprintf("%s(%s)", f.fnType, p.Expr("arg", f.column))
 }
 
 func (f aggregateFunc) Relations() []string {
@@ -43,6 +67,7 @@ type aggregationType int
 
 const (
 	avg aggregationType = iota
+	count
 	max
 	min
 	sum
@@ -52,6 +77,8 @@ func (a aggregationType) String() string {
 	switch a {
 	case avg:
 		return "AVG"
+	case count:
+		return "COUNT"
 	case max:
 		return "MAX"
 	case min:
@@ -59,6 +86,10 @@ func (a aggregationType) String() string {
 	case sum:
 		return "SUM"
 	default:
-		panic("unknown aggregationType")
+		return unknownEnum(a)
 	}
 }
+
+func (a aggregationType) valid() bool {
+	return a >= avg && a <= sum
+}
@@ -0,0 +1,106 @@
+package psql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// An ArgCountError is returned when the number of arguments supplied for
+// a query differs from the number of its positional free parameters.
+type ArgCountError struct {
+	// Params describes the positional free parameters in the query.
+	Params []ParamInfo
+
+	// Got is the number of arguments that were supplied.
+	Got int
+}
+
+func (e *ArgCountError) Error() string {
+	descs := make([]string, len(e.Params))
+	for i, info := range e.Params {
+		descs[i] = info.String()
+	}
+
+	msg := fmt.Sprintf("psql: query has %d free parameters", len(e.Params))
+	if len(descs) > 0 {
+		msg += fmt.Sprintf(" [%s]", strings.Join(descs, ", "))
+	}
+
+	return fmt.Sprintf("%s, but %d arguments were supplied", msg, e.Got)
+}
+
+// An ArgTypeError is returned when the Go type of an argument cannot be
+// used as the value of the free parameter it was supplied for.
+type ArgTypeError struct {
+	Param ParamInfo
+	Value interface{}
+}
+
+func (e *ArgTypeError) Error() string {
+	msg := fmt.Sprintf("psql: argument of type %T cannot be used for parameter %s", e.Value, e.Param)
+	if e.Param.Type.dims > 0 {
+		msg += "; array arguments must be wrapped with pq.Array"
+	}
+	return msg
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// compatible reports whether value can be supplied as the argument for the
+// free parameter described by info. Values whose suitability cannot be
+// determined in advance, such as NULLs and driver.Valuers, are always
+// considered compatible.
+func compatible(info ParamInfo, value interface{}) bool {
+	if value == nil || info.Type == unknownType {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().Implements(valuerType) {
+		return true
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+
+	// Slices other than []byte cannot be sent to the database unless they
+	// are wrapped in a driver.Valuer such as pq.Array.
+	if info.Type.dims > 0 {
+		return false
+	}
+
+	kind, typ := rv.Kind(), rv.Type()
+	isString := kind == reflect.String
+	isBytes := kind == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+	isInt := kind >= reflect.Int && kind <= reflect.Uint64
+	isFloat := kind == reflect.Float32 || kind == reflect.Float64
+
+	switch info.Type.kind {
+	case textKind, varcharKind, charKind, uuidKind, byteaKind, jsonKind, jsonbKind:
+		return isString || isBytes
+	case smallIntKind, intKind, bigIntKind:
+		return isInt
+	case realKind, floatKind:
+		return isInt || isFloat
+	case numericKind:
+		return isInt || isFloat || isString
+	case boolKind:
+		return kind == reflect.Bool
+	case timestampKind, timestampTZKind, dateKind, timeKind:
+		return typ == timeType || isString
+	case intervalKind:
+		return isString
+	default:
+		return true
+	}
+}
@@ -0,0 +1,88 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestCheckedBindings(t *testing.T) {
+	query := Select(
+		StringLiteral("Hello"),
+	).Where(
+		Eq(TableColumn("users", "name"), StringParam()),
+		GreaterThan(TableColumn("users", "height"), IntParam()),
+	)
+
+	want := []ParamInfo{
+		{Position: 2, Type: TextType()},
+		{Position: 3, Type: IntType()},
+	}
+	if got := query.FreeParams(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	bindings, err := query.CheckedBindings("Joe", 180)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if want := []interface{}{"Hello", "Joe", 180}; !reflect.DeepEqual(bindings, want) {
+		t.Errorf("expected %v, got %v", want, bindings)
+	}
+
+	for _, inputs := range [][]interface{}{{"Joe"}, {"Joe", 180, "extra"}} {
+		_, err := query.CheckedBindings(inputs...)
+		if cerr, ok := err.(*ArgCountError); !ok {
+			t.Errorf("expected an *ArgCountError, got %v", err)
+		} else if cerr.Got != len(inputs) || len(cerr.Params) != 2 {
+			t.Errorf("unexpected error contents: %+v", cerr)
+		}
+	}
+
+	// CheckedBindings doesn't look at the types of its inputs.
+	if _, err := query.CheckedBindings(180, "Joe"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err = query.StrictBindings(180, "Joe")
+	if terr, ok := err.(*ArgTypeError); !ok {
+		t.Errorf("expected an *ArgTypeError, got %v", err)
+	} else if terr.Param.Position != 2 || terr.Value != 180 {
+		t.Errorf("unexpected error contents: %+v", terr)
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	name := "Joe"
+
+	cases := []struct {
+		info  ParamInfo
+		value interface{}
+		ok    bool
+	}{
+		{ParamInfo{Type: TextType()}, "Joe", true},
+		{ParamInfo{Type: TextType()}, &name, true},
+		{ParamInfo{Type: TextType()}, (*string)(nil), true},
+		{ParamInfo{Type: TextType()}, nil, true},
+		{ParamInfo{Type: TextType()}, 42, false},
+		{ParamInfo{Type: IntType()}, int64(42), true},
+		{ParamInfo{Type: IntType()}, "42", false},
+		{ParamInfo{Type: FloatType()}, 1.5, true},
+		{ParamInfo{Type: BoolType()}, true, true},
+		{ParamInfo{Type: BoolType()}, 1, false},
+		{ParamInfo{Type: TimestampTZType()}, time.Now(), true},
+		{ParamInfo{Type: TimestampTZType()}, 42, false},
+		{ParamInfo{Type: ByteaType()}, []byte("abc"), true},
+		{ParamInfo{Type: IntervalType()}, time.Second, false},
+		{ParamInfo{Type: ArrayOf(IntType())}, []int64{1, 2}, false},
+		{ParamInfo{Type: ArrayOf(IntType())}, pq.Array([]int64{1, 2}), true},
+		{ParamInfo{Type: unknownType}, struct{}{}, true},
+	}
+
+	for i, tc := range cases {
+		if ok := compatible(tc.info, tc.value); ok != tc.ok {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.ok, ok)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Case returns a searched CASE expression, whose result is that of the
+// first When clause whose condition is true:
+//
+//	CASE WHEN cond THEN result ... ELSE result END
+//
+// If no condition is true, the result is that of the Else clause, or NULL
+// if there isn't one.
+func Case() searchedCase {
+	return searchedCase{}
+}
+
+// CaseOf returns a simple CASE expression, whose result is that of the
+// first When clause whose value is equal to expr:
+//
+//	CASE expr WHEN value THEN result ... ELSE result END
+func CaseOf(expr Expression) simpleCase {
+	return simpleCase{caseExpr{operand: expr}}
+}
+
+type searchedCase struct {
+	caseExpr
+}
+
+// When returns a copy of c with an additional branch, whose result is
+// used when cond is true.
+func (c searchedCase) When(cond BooleanExpression, result Expression) searchedCase {
+	c.whens = append(c.whens[:len(c.whens):len(c.whens)], when{cond, result})
+	return c
+}
+
+// Else returns a copy of c whose result is expr when no branch applies.
+func (c searchedCase) Else(expr Expression) searchedCase {
+	c.els = expr
+	return c
+}
+
+type simpleCase struct {
+	caseExpr
+}
+
+// When returns a copy of c with an additional branch, whose result is
+// used when the operand of c is equal to value.
+func (c simpleCase) When(value, result Expression) simpleCase {
+	c.whens = append(c.whens[:len(c.whens):len(c.whens)], when{value, result})
+	return c
+}
+
+// Else returns a copy of c whose result is expr when no branch applies.
+func (c simpleCase) Else(expr Expression) simpleCase {
+	c.els = expr
+	return c
+}
+
+// CaseBool is like Case, but every result is a BooleanExpression, and so
+// is the CASE expression itself, which can be used as a condition.
+func CaseBool() searchedBoolCase {
+	return searchedBoolCase{}
+}
+
+// CaseOfBool is like CaseOf, but every result is a BooleanExpression, and
+// so is the CASE expression itself, which can be used as a condition.
+func CaseOfBool(expr Expression) simpleBoolCase {
+	return simpleBoolCase{boolCaseExpr{caseExpr{operand: expr}}}
+}
+
+type searchedBoolCase struct {
+	boolCaseExpr
+}
+
+// When returns a copy of c with an additional branch, whose result is
+// used when cond is true.
+func (c searchedBoolCase) When(cond, result BooleanExpression) searchedBoolCase {
+	c.whens = append(c.whens[:len(c.whens):len(c.whens)], when{cond, result})
+	return c
+}
+
+// Else returns a copy of c whose result is expr when no branch applies.
+func (c searchedBoolCase) Else(expr BooleanExpression) searchedBoolCase {
+	c.els = expr
+	return c
+}
+
+type simpleBoolCase struct {
+	boolCaseExpr
+}
+
+// When returns a copy of c with an additional branch, whose result is
+// used when the operand of c is equal to value.
+func (c simpleBoolCase) When(value Expression, result BooleanExpression) simpleBoolCase {
+	c.whens = append(c.whens[:len(c.whens):len(c.whens)], when{value, result})
+	return c
+}
+
+// Else returns a copy of c whose result is expr when no branch applies.
+func (c simpleBoolCase) Else(expr BooleanExpression) simpleBoolCase {
+	c.els = expr
+	return c
+}
+
+type when struct {
+	cond, result Expression
+}
+
+// caseExpr holds the parts common to searched and simple CASE expressions.
+// The operand is nil for a searched CASE.
+type caseExpr struct {
+	operand Expression
+	whens   []when
+	els     Expression
+}
+
+func (c caseExpr) ToSQLExpr(p *Params) string {
+	return c.toSQL(p, func(label string, e Expression) string {
+		return p.Expr(label, e)
+	})
+}
+
+// boolCaseExpr is a caseExpr whose results are all BooleanExpressions, as
+// guaranteed by the signatures of CaseBool and CaseOfBool.
+type boolCaseExpr struct {
+	caseExpr
+}
+
+func (c boolCaseExpr) ToSQLBoolean(p *Params) string {
+	return c.toSQL(p, func(label string, e Expression) string {
+		return p.Boolean(label, e.(BooleanExpression))
+	})
+}
+
+func (c caseExpr) toSQL(p *Params, result func(string, Expression) string) string {
+	if len(c.whens) == 0 {
+		p.Errorf("CASE requires at least one WHEN clause")
+	}
+
+	parts := []string{"CASE"}
+	if c.operand != nil {
+		parts = append(parts, p.Expr("operand", c.operand))
+	}
+
+	for i, w := range c.whens {
+		label := fmt.Sprintf("whens[%d]", i)
+		var cond string
+		if b, ok := w.cond.(BooleanExpression); ok && c.operand == nil {
+			cond = p.Boolean(label+".cond", b)
+		} else {
+			cond = p.Expr(label+".value", w.cond)
+		}
+		parts = append(parts, "WHEN", cond, "THEN", result(label+".result", w.result))
+	}
+
+	if c.els != nil {
+		parts = append(parts, "ELSE", result("else", c.els))
+	}
+
+	return strings.Join(append(parts, "END"), " ")
+}
+
+func (c caseExpr) Relations() []string {
+	var rels []string
+	if c.operand != nil {
+		rels = append(rels, c.operand.Relations()...)
+	}
+	for _, w := range c.whens {
+		rels = append(rels, w.cond.Relations()...)
+		rels = append(rels, w.result.Relations()...)
+	}
+	if c.els != nil {
+		rels = append(rels, c.els.Relations()...)
+	}
+	return rels
+}
@@ -0,0 +1,102 @@
+package psql
+
+import "testing"
+
+func TestCaseSQL(t *testing.T) {
+	age := TableColumn("users", "age")
+	status := TableColumn("orders", "status")
+
+	cases := []struct {
+		query SelectQuery
+		sql   string
+	}{
+		{
+			Select(
+				Case().
+					When(LessThan(age, IntLiteral(13)), StringLiteral("child")).
+					When(LessThan(age, IntLiteral(18)), StringLiteral("teen")).
+					Else(StringLiteral("adult")),
+			),
+			`SELECT CASE WHEN ("age" < 13) THEN $1::text WHEN ("age" < 18) THEN $2::text ELSE $3::text END FROM "users"`,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+				As(CaseOf(status).When(StringLiteral("shipped"), IntLiteral(1)), "shipped"),
+			),
+			`SELECT "name", CASE "status" WHEN $1::text THEN 1 END AS "shipped" FROM "users", "orders"`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				And(
+					IsNotNull(age),
+					CaseBool().
+						When(Eq(TableColumn("users", "role"), StringLiteral("admin")), BoolLiteral(true)).
+						Else(GreaterThanOrEq(age, IntParam())),
+				),
+			),
+			`SELECT "name" FROM "users" WHERE ("age" IS NOT NULL AND CASE WHEN ("role" = $1::text) THEN $2::boolean ELSE ("age" >= $3::integer) END)`,
+		},
+		{
+			Select(TableColumn("orders", "id")).Where(
+				CaseOfBool(status).
+					When(StringLiteral("shipped"), IsNotNull(TableColumn("orders", "shipped_at"))).
+					Else(BoolLiteral(false)),
+			),
+			`SELECT "id" FROM "orders" WHERE CASE "status" WHEN $1::text THEN "shipped_at" IS NOT NULL ELSE $2::boolean END`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+}
+
+func TestCaseErrors(t *testing.T) {
+	age := TableColumn("users", "age")
+
+	cases := []struct {
+		query SelectQuery
+		err   string
+	}{
+		{
+			Select(CaseOf(age).Else(IntLiteral(0))),
+			"psql: SELECT[0]: CASE requires at least one WHEN clause",
+		},
+		{
+			Select(age).Where(CaseBool()),
+			"psql: WHERE[0]: CASE requires at least one WHEN clause",
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.query.Build()
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+	}
+}
+
+func TestCaseImmutable(t *testing.T) {
+	age := TableColumn("users", "age")
+	base := Case().When(IsNull(age), IntLiteral(0))
+
+	a := base.When(LessThan(age, IntLiteral(18)), IntLiteral(1))
+	b := base.When(GreaterThan(age, IntLiteral(65)), IntLiteral(2))
+
+	if sql := Select(a).ToSQL(); sql != `SELECT CASE WHEN "age" IS NULL THEN 0 WHEN ("age" < 18) THEN 1 END FROM "users"` {
+		t.Errorf("unexpected SQL %q", sql)
+	}
+
+	if sql := Select(b).ToSQL(); sql != `SELECT CASE WHEN "age" IS NULL THEN 0 WHEN ("age" > 65) THEN 2 END FROM "users"` {
+		t.Errorf("unexpected SQL %q", sql)
+	}
+}
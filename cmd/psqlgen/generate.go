@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// initialisms are the words that Go style writes entirely in upper case
+// when they appear in an identifier.
+var initialisms = map[string]bool{
+	"api": true, "ascii": true, "cpu": true, "css": true, "dns": true,
+	"html": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "sql": true, "ssh": true, "tls": true, "ttl": true,
+	"uid": true, "ui": true, "uri": true, "url": true, "utf8": true,
+	"uuid": true, "xml": true,
+}
+
+// reservedNames are the identifiers declared by every generated package,
+// which columns must not collide with.
+var reservedNames = map[string]bool{
+	"Table":   true,
+	"All":     true,
+	"Columns": true,
+}
+
+// goName converts an SQL identifier such as "user_id" into an exported Go
+// identifier such as "UserID".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			sb.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		sb.WriteRune(unicode.ToUpper(r[0]))
+		sb.WriteString(string(r[1:]))
+	}
+
+	id := sb.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// packageName converts a table name into a Go package name, which is
+// lower case and contains no underscores, unless the name would otherwise
+// be a Go keyword such as "type", in which case it is followed by one.
+func packageName(t *table) string {
+	name := t.name
+	if t.schemaName() != "" {
+		name = t.schema + "_" + t.name
+	}
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	pkg := sb.String()
+	if pkg == "" || !unicode.IsLetter([]rune(pkg)[0]) {
+		pkg = "t" + pkg
+	}
+	if gotoken.IsKeyword(pkg) {
+		pkg += "_"
+	}
+	return pkg
+}
+
+type templateColumn struct {
+	GoName string
+	Name   string
+	Doc    string
+}
+
+type templateData struct {
+	Package string
+	Schema  string
+	Table   string
+	Quoted  string
+	Columns []templateColumn
+}
+
+var fileTemplate = template.Must(template.New("table").Parse(`// Code generated by psqlgen. DO NOT EDIT.
+
+// Package {{.Package}} contains psql expressions for the {{.Quoted}} table.
+package {{.Package}}
+
+import "github.com/leocassarani/psql"
+
+// Table is the {{.Quoted}} table.
+var Table = psql.SchemaTable({{printf "%q" .Schema}}, {{printf "%q" .Table}})
+
+// All represents all of the columns of the {{.Quoted}} table.
+var All = Table.AllColumns()
+
+var (
+{{- range .Columns}}
+	// {{.GoName}} is {{.Doc}}
+	{{.GoName}} = Table.Column({{printf "%q" .Name}})
+{{end -}}
+)
+
+// Columns returns every column of the {{.Quoted}} table, in the order
+// in which they are defined.
+func Columns() []psql.Expression {
+	return []psql.Expression{
+{{- range .Columns}}
+		{{.GoName}},
+{{- end}}
+	}
+}
+`))
+
+// generate returns the source code of the Go package for the table t.
+func generate(t *table) ([]byte, error) {
+	data := templateData{
+		Package: packageName(t),
+		Schema:  t.schemaName(),
+		Table:   t.name,
+		Quoted:  quoteIdent(t.name),
+	}
+
+	if data.Schema != "" {
+		data.Quoted = quoteIdent(data.Schema) + "." + data.Quoted
+	}
+
+	seen := make(map[string]string)
+	for _, c := range t.columns {
+		name := goName(c.name)
+		if reservedNames[name] {
+			name += "Column"
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("columns %q and %q of table %s both map to the Go name %s", other, c.name, data.Quoted, name)
+		}
+		seen[name] = c.name
+
+		data.Columns = append(data.Columns, templateColumn{
+			GoName: name,
+			Name:   c.name,
+			Doc:    columnDoc(c),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// columnDoc describes the column c in a sentence, for use in a comment.
+func columnDoc(c *column) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "the %s column, of type %s", quoteIdent(c.name), c.dataType)
+
+	if c.notNull && !c.primaryKey {
+		sb.WriteString(" NOT NULL")
+	}
+
+	var notes []string
+	switch {
+	case c.primaryKey:
+		notes = append(notes, "It is part of the primary key.")
+	case c.unique:
+		notes = append(notes, "It is unique.")
+	}
+	switch {
+	case c.generated:
+		notes = append(notes, "It is generated by the database.")
+	case c.hasDefault:
+		notes = append(notes, "It has a default value.")
+	}
+
+	sb.WriteString(".")
+	for _, note := range notes {
+		sb.WriteString(" " + note)
+	}
+
+	return sb.String()
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
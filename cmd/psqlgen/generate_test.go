@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"email":        "Email",
+		"user_id":      "UserID",
+		"avatar_url":   "AvatarURL",
+		"Display Name": "DisplayName",
+		"2fa_enabled":  "X2faEnabled",
+		"uuid":         "UUID",
+	}
+
+	for in, out := range cases {
+		if name := goName(in); name != out {
+			t.Errorf("goName(%q): expected %q, got %q", in, out, name)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tbl := &table{
+		schema: "analytics",
+		name:   "page_views",
+		columns: []*column{
+			{name: "id", dataType: "bigint", notNull: true, primaryKey: true, hasDefault: true},
+			{name: "url", dataType: "text", notNull: true},
+			{name: "table", dataType: "text", unique: true},
+			{name: "search", dataType: "tsvector", generated: true},
+		},
+	}
+
+	src, err := generate(tbl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `// Code generated by psqlgen. DO NOT EDIT.
+
+// Package analyticspageviews contains psql expressions for the "analytics"."page_views" table.
+package analyticspageviews
+
+import "github.com/leocassarani/psql"
+
+// Table is the "analytics"."page_views" table.
+var Table = psql.SchemaTable("analytics", "page_views")
+
+// All represents all of the columns of the "analytics"."page_views" table.
+var All = Table.AllColumns()
+
+var (
+	// ID is the "id" column, of type bigint. It is part of the primary key. It has a default value.
+	ID = Table.Column("id")
+
+	// URL is the "url" column, of type text NOT NULL.
+	URL = Table.Column("url")
+
+	// TableColumn is the "table" column, of type text. It is unique.
+	TableColumn = Table.Column("table")
+
+	// Search is the "search" column, of type tsvector. It is generated by the database.
+	Search = Table.Column("search")
+)
+
+// Columns returns every column of the "analytics"."page_views" table, in the order
+// in which they are defined.
+func Columns() []psql.Expression {
+	return []psql.Expression{
+		ID,
+		URL,
+		TableColumn,
+		Search,
+	}
+}
+`
+
+	if string(src) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+	}
+
+	tbl.columns = append(tbl.columns, &column{name: "URL", dataType: "text"})
+	if _, err := generate(tbl); err == nil {
+		t.Error("expected an error for colliding column names, got nil")
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	cases := []struct {
+		table *table
+		pkg   string
+	}{
+		{&table{name: "user_roles"}, "userroles"},
+		{&table{schema: "public", name: "users"}, "users"},
+		{&table{schema: "analytics", name: "events"}, "analyticsevents"},
+		{&table{name: "type"}, "type_"},
+		{&table{name: "select"}, "select_"},
+		{&table{name: "events.2024"}, "events2024"},
+		{&table{name: "2024"}, "t2024"},
+	}
+
+	for _, tc := range cases {
+		if pkg := packageName(tc.table); pkg != tc.pkg {
+			t.Errorf("packageName(%q): expected %q, got %q", tc.table.qualifiedName(), tc.pkg, pkg)
+		}
+	}
+}
+
+func TestGenerateDottedName(t *testing.T) {
+	src, err := generate(&table{name: "events.2024", columns: []*column{{name: "id", dataType: "bigint"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `var Table = psql.SchemaTable("", "events.2024")`; !strings.Contains(string(src), want) {
+		t.Errorf("expected the generated code to contain %q, got:\n%s", want, src)
+	}
+}
+
+func TestRunPackageCollision(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.sql")
+	src := "CREATE TABLE user_roles (id int);\nCREATE TABLE userroles (id int);\n"
+	if err := os.WriteFile(schema, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := run(filepath.Join(dir, "out"), "", []string{schema})
+	if msg := "tables user_roles and userroles both map to the package name userroles"; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
@@ -0,0 +1,136 @@
+// Command psqlgen generates Go packages containing psql expressions for the
+// tables and columns defined by an SQL schema.
+//
+// The schema is read from the files given as arguments, which may be the
+// output of pg_dump --schema-only, or directories of migrations. The .sql
+// files in a directory are read in lexical order, so that migrations named
+// with a sequence number or timestamp are applied in the right order.
+//
+// Usage:
+//
+//	psqlgen [-out dir] [-tables name,...] schema.sql|migrations/ ...
+//
+// For each table, psqlgen writes a package to a subdirectory of the output
+// directory, named after the table. The package for a table called "users"
+// looks like this:
+//
+//	package users
+//
+//	var Table = psql.SchemaTable("", "users")
+//
+//	var (
+//		ID    = Table.Column("id")
+//		Email = Table.Column("email")
+//	)
+//
+// which means that queries can refer to users.Email instead of repeating the
+// table and column names as strings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	out := flag.String("out", ".", "directory in which to write the generated packages")
+	only := flag.String("tables", "", "comma-separated list of tables to generate (default all)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: psqlgen [-out dir] [-tables name,...] schema.sql|migrations/ ...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*out, *only, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "psqlgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out, only string, paths []string) error {
+	s := &schema{}
+
+	for _, path := range paths {
+		files, err := schemaFiles(path)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			src, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if err := s.parse(string(src)); err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+
+	packages := make(map[string]string)
+	for _, t := range s.tables {
+		if len(wanted) > 0 && !wanted[t.name] && !wanted[t.qualifiedName()] {
+			continue
+		}
+
+		pkg := packageName(t)
+		if other, ok := packages[pkg]; ok {
+			return fmt.Errorf("tables %s and %s both map to the package name %s", other, t.qualifiedName(), pkg)
+		}
+		packages[pkg] = t.qualifiedName()
+
+		src, err := generate(t)
+		if err != nil {
+			return err
+		}
+
+		dir := filepath.Join(out, pkg)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, pkg+".go"), src, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaFiles returns path if it is a file, or the .sql files it contains
+// in lexical order if it is a directory.
+func schemaFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
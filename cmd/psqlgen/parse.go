@@ -0,0 +1,812 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A schema holds the tables defined by a sequence of SQL statements, in
+// the order they were created.
+type schema struct {
+	tables []*table
+}
+
+type table struct {
+	schema, name string
+	columns      []*column
+}
+
+type column struct {
+	name     string
+	dataType string
+
+	notNull    bool
+	primaryKey bool
+	unique     bool
+	hasDefault bool
+	generated  bool
+}
+
+// qualifiedName returns the name of t, preceded by its schema unless it is
+// in the default schema, as used by the -tables flag.
+// Tables in the default "public" schema are left unqualified.
+func (t *table) qualifiedName() string {
+	if t.schema == "" || t.schema == "public" {
+		return t.name
+	}
+	return t.schema + "." + t.name
+}
+
+// schemaName returns the schema of t as it should be passed to
+// psql.SchemaTable, which is empty for tables in the default schema.
+func (t *table) schemaName() string {
+	if t.schema == "public" {
+		return ""
+	}
+	return t.schema
+}
+
+func (t *table) column(name string) *column {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *schema) table(schemaName, name string) *table {
+	for _, t := range s.tables {
+		if t.name == name && sameSchema(t.schema, schemaName) {
+			return t
+		}
+	}
+	return nil
+}
+
+func sameSchema(a, b string) bool {
+	if a == "" {
+		a = "public"
+	}
+	if b == "" {
+		b = "public"
+	}
+	return a == b
+}
+
+// parse reads the CREATE TABLE, ALTER TABLE and DROP TABLE statements in
+// src and applies them to s. Other statements, such as CREATE INDEX or
+// CREATE FUNCTION, are ignored.
+func (s *schema) parse(src string) error {
+	tokens, err := lex(src)
+	if err != nil {
+		return err
+	}
+
+	for len(tokens) > 0 {
+		end := 0
+		for end < len(tokens) && !tokens[end].is(";") {
+			end++
+		}
+
+		p := &parser{tokens: tokens[:end]}
+		if err := p.statement(s); err != nil {
+			return err
+		}
+
+		if end < len(tokens) {
+			end++
+		}
+		tokens = tokens[end:]
+	}
+
+	return nil
+}
+
+type tokenKind int
+
+const (
+	word tokenKind = iota
+	quotedIdent
+	stringLit
+	number
+	punct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// is reports whether t is the given keyword or punctuation. Keywords are
+// matched case-insensitively, and never match quoted identifiers.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case word:
+		return strings.EqualFold(t.text, s)
+	case punct:
+		return t.text == s
+	default:
+		return false
+	}
+}
+
+// lex splits src into tokens, discarding whitespace and comments. Unquoted
+// words are folded to lower case, as PostgreSQL does.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	r := []rune(src)
+
+	for i := 0; i < len(r); {
+		c := r[i]
+		start := line
+
+		switch {
+		case c == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			depth := 0
+			for ; i < len(r); i++ {
+				if r[i] == '\n' {
+					line++
+				} else if r[i] == '/' && i+1 < len(r) && r[i+1] == '*' {
+					depth++
+					i++
+				} else if r[i] == '*' && i+1 < len(r) && r[i+1] == '/' {
+					depth--
+					i++
+					if depth == 0 {
+						i++
+						break
+					}
+				}
+			}
+			if depth > 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+
+		case c == '"' || c == '\'':
+			var sb strings.Builder
+			closed := false
+			for i++; i < len(r); i++ {
+				if r[i] == '\n' {
+					line++
+				}
+				if r[i] == c {
+					if i+1 < len(r) && r[i+1] == c {
+						sb.WriteRune(c)
+						i++
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(r[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("line %d: unterminated quoted string", start)
+			}
+			kind := stringLit
+			if c == '"' {
+				kind = quotedIdent
+			}
+			tokens = append(tokens, token{kind, sb.String(), start})
+
+		case c == '$' && dollarTag(r[i:]) != "":
+			tag := dollarTag(r[i:])
+			body := string(r[i+len([]rune(tag)):])
+			end := strings.Index(body, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated dollar-quoted string", start)
+			}
+			text := body[:end]
+			line += strings.Count(text, "\n")
+			i += len([]rune(tag))*2 + len([]rune(text))
+			tokens = append(tokens, token{stringLit, text, start})
+
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '_' || r[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, token{word, strings.ToLower(string(r[i:j])), start})
+			i = j
+
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{number, string(r[i:j]), start})
+			i = j
+
+		default:
+			tokens = append(tokens, token{punct, string(c), start})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// dollarTag returns the opening tag of a dollar-quoted string at the start
+// of r, such as "$$" or "$body$", or "" if there isn't one.
+func dollarTag(r []rune) string {
+	for i := 1; i < len(r); i++ {
+		if r[i] == '$' {
+			return string(r[:i+1])
+		}
+		if !(unicode.IsLetter(r[i]) || r[i] == '_' || (i > 1 && unicode.IsDigit(r[i]))) {
+			return ""
+		}
+	}
+	return ""
+}
+
+// A parser consumes the tokens of a single statement.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{kind: punct, line: p.line()}
+}
+
+func (p *parser) line() int {
+	if len(p.tokens) == 0 {
+		return 0
+	}
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].line
+	}
+	return p.tokens[len(p.tokens)-1].line
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the given sequence of keywords if they come next, and
+// reports whether it did.
+func (p *parser) accept(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// acceptAny consumes the next token if it is one of the given keywords.
+func (p *parser) acceptAny(words ...string) bool {
+	for _, w := range words {
+		if p.accept(w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return p.errorf("expected %s, found %s", strings.ToUpper(strings.Join(words, " ")), p.found())
+	}
+	return nil
+}
+
+// found describes the next token, for use in error messages.
+func (p *parser) found() string {
+	if p.done() {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", p.peek().text)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line(), fmt.Sprintf(format, args...))
+}
+
+func (p *parser) ident() (string, error) {
+	if t := p.peek(); t.kind != word && t.kind != quotedIdent {
+		return "", p.errorf("expected an identifier, found %s", p.found())
+	}
+	return p.next().text, nil
+}
+
+// qualifiedIdent parses a possibly schema-qualified name.
+func (p *parser) qualifiedIdent() (schemaName, name string, err error) {
+	name, err = p.ident()
+	if err != nil {
+		return "", "", err
+	}
+
+	if p.accept(".") {
+		schemaName = name
+		if name, err = p.ident(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return schemaName, name, nil
+}
+
+// identList parses a parenthesised, comma-separated list of identifiers.
+func (p *parser) identList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		// Skip options such as ASC or a collation in index definitions.
+		for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+			p.next()
+		}
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	return names, p.expect(")")
+}
+
+// skip consumes tokens up to, but not including, the next token at the
+// current nesting depth for which stop returns true.
+func (p *parser) skip(stop func(token) bool) []token {
+	start := p.pos
+	depth := 0
+
+	for !p.done() {
+		t := p.peek()
+		if depth == 0 && (stop(t) || t.is(")")) {
+			break
+		}
+		if t.is("(") || t.is("[") {
+			depth++
+		} else if t.is(")") || t.is("]") {
+			depth--
+		}
+		p.next()
+	}
+
+	return p.tokens[start:p.pos]
+}
+
+func (p *parser) statement(s *schema) error {
+	switch {
+	case p.accept("create"):
+		p.acceptAny("global", "local")
+		p.acceptAny("temp", "temporary", "unlogged")
+		if p.accept("table") {
+			return p.createTable(s)
+		}
+	case p.accept("alter", "table"):
+		return p.alterTable(s)
+	case p.accept("drop", "table"):
+		return p.dropTable(s)
+	}
+	return nil
+}
+
+func (p *parser) createTable(s *schema) error {
+	p.accept("if", "not", "exists")
+
+	schemaName, name, err := p.qualifiedIdent()
+	if err != nil {
+		return err
+	}
+
+	// CREATE TABLE ... AS and CREATE TABLE ... PARTITION OF don't list
+	// their columns, so there is nothing to generate for them.
+	if !p.peek().is("(") {
+		return nil
+	}
+	p.next()
+
+	t := &table{schema: schemaName, name: name}
+	for !p.accept(")") {
+		if err := p.tableElement(t); err != nil {
+			return err
+		}
+		if !p.accept(",") && !p.peek().is(")") {
+			return p.errorf("expected , or ) in definition of table %q, found %s", name, p.found())
+		}
+	}
+
+	if existing := s.table(schemaName, name); existing != nil {
+		*existing = *t
+	} else {
+		s.tables = append(s.tables, t)
+	}
+
+	return nil
+}
+
+func (p *parser) tableElement(t *table) error {
+	switch {
+	case p.peek().is("constraint"), p.peek().is("primary"), p.peek().is("unique"),
+		p.peek().is("foreign"), p.peek().is("check"), p.peek().is("exclude"):
+		return p.tableConstraint(t)
+	case p.accept("like"):
+		p.skip(isComma)
+		return nil
+	}
+
+	c, err := p.columnDef()
+	if err != nil {
+		return err
+	}
+	t.columns = append(t.columns, c)
+	return nil
+}
+
+func (p *parser) tableConstraint(t *table) error {
+	if p.accept("constraint") {
+		if _, err := p.ident(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.accept("primary", "key"):
+		names, err := p.identList()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			c := t.column(name)
+			if c == nil {
+				return p.errorf("primary key of table %q refers to unknown column %q", t.name, name)
+			}
+			c.primaryKey = true
+			c.notNull = true
+		}
+	case p.accept("unique"):
+		p.accept("nulls", "not", "distinct")
+		names, err := p.identList()
+		if err != nil {
+			return err
+		}
+		if len(names) == 1 {
+			if c := t.column(names[0]); c != nil {
+				c.unique = true
+			}
+		}
+	}
+
+	p.skip(isComma)
+	return nil
+}
+
+func isComma(t token) bool {
+	return t.is(",")
+}
+
+// columnConstraintKeywords are the keywords that can follow a column's data
+// type, and so mark its end.
+var columnConstraintKeywords = []string{
+	"not", "null", "default", "primary", "unique", "references", "check",
+	"constraint", "collate", "generated", "deferrable", "initially",
+}
+
+func isColumnConstraint(t token) bool {
+	if t.is(",") {
+		return true
+	}
+	for _, kw := range columnConstraintKeywords {
+		if t.is(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) columnDef() (*column, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	typ := p.skip(isColumnConstraint)
+	if len(typ) == 0 {
+		return nil, p.errorf("column %q has no data type", name)
+	}
+
+	c := &column{name: name, dataType: typeName(typ)}
+	switch c.dataType {
+	case "serial", "bigserial", "smallserial", "serial4", "serial8", "serial2":
+		c.hasDefault = true
+		c.notNull = true
+	}
+
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		if err := p.columnConstraint(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (p *parser) columnConstraint(c *column) error {
+	switch {
+	case p.accept("constraint"):
+		_, err := p.ident()
+		return err
+	case p.accept("not", "null"):
+		c.notNull = true
+	case p.accept("null"):
+		c.notNull = false
+	case p.accept("primary", "key"):
+		c.primaryKey = true
+		c.notNull = true
+	case p.accept("unique"):
+		c.unique = true
+	case p.accept("default"):
+		c.hasDefault = true
+		p.skip(isColumnConstraint)
+	case p.accept("generated"):
+		// Identity columns declared BY DEFAULT can still be assigned
+		// explicitly, unlike those that are GENERATED ALWAYS.
+		if p.accept("by", "default") {
+			c.hasDefault = true
+		} else {
+			c.generated = true
+		}
+		p.skip(isColumnConstraint)
+	case p.accept("references"):
+		return p.references()
+	default:
+		// REFERENCES, CHECK, COLLATE and the like don't affect the
+		// generated code, so they are skipped along with their arguments.
+		p.next()
+		p.skip(isColumnConstraint)
+	}
+	return nil
+}
+
+// references parses the remainder of a REFERENCES column constraint, whose
+// referential actions may contain keywords such as NULL and DEFAULT that
+// would otherwise be mistaken for other constraints.
+func (p *parser) references() error {
+	if _, _, err := p.qualifiedIdent(); err != nil {
+		return err
+	}
+
+	if p.peek().is("(") {
+		if _, err := p.identList(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch {
+		case p.accept("match"):
+			p.next()
+		case p.accept("on"):
+			p.acceptAny("delete", "update")
+			switch {
+			case p.accept("set"):
+				p.acceptAny("null", "default")
+				if p.peek().is("(") {
+					if _, err := p.identList(); err != nil {
+						return err
+					}
+				}
+			case p.accept("no", "action"):
+			default:
+				p.acceptAny("restrict", "cascade")
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// typeName reconstructs the text of a data type from its tokens, such as
+// "numeric(10,2)" or "timestamp with time zone".
+func typeName(tokens []token) string {
+	var sb strings.Builder
+
+	for i, t := range tokens {
+		text := t.text
+		if t.kind == quotedIdent {
+			text = `"` + strings.Replace(text, `"`, `""`, -1) + `"`
+		}
+
+		if i > 0 {
+			prev := tokens[i-1]
+			attached := t.is("(") || t.is(")") || t.is("[") || t.is("]") || t.is(",") || t.is(".")
+			if !attached && !prev.is("(") && !prev.is("[") && !prev.is(".") && !prev.is(",") {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(text)
+	}
+
+	return sb.String()
+}
+
+func (p *parser) alterTable(s *schema) error {
+	p.accept("if", "exists")
+	p.accept("only")
+
+	schemaName, name, err := p.qualifiedIdent()
+	if err != nil {
+		return err
+	}
+	p.accept("*")
+
+	t := s.table(schemaName, name)
+	if t == nil {
+		// The table may have been created by a statement we don't
+		// understand, such as CREATE TABLE ... AS.
+		return nil
+	}
+
+	if p.accept("rename", "to") {
+		t.name, err = p.ident()
+		return err
+	}
+
+	for !p.done() {
+		if err := p.alterAction(t); err != nil {
+			return err
+		}
+		p.skip(isComma)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) alterAction(t *table) error {
+	switch {
+	case p.accept("add"):
+		switch {
+		case p.peek().is("constraint"), p.peek().is("primary"), p.peek().is("unique"),
+			p.peek().is("foreign"), p.peek().is("check"), p.peek().is("exclude"):
+			return p.tableConstraint(t)
+		}
+		p.accept("column")
+		p.accept("if", "not", "exists")
+		c, err := p.columnDef()
+		if err != nil {
+			return err
+		}
+		if t.column(c.name) == nil {
+			t.columns = append(t.columns, c)
+		}
+
+	case p.accept("drop"):
+		if p.peek().is("constraint") {
+			return nil
+		}
+		p.accept("column")
+		p.accept("if", "exists")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		for i, c := range t.columns {
+			if c.name == name {
+				t.columns = append(t.columns[:i], t.columns[i+1:]...)
+				break
+			}
+		}
+
+	case p.accept("rename"):
+		if p.peek().is("constraint") {
+			return nil
+		}
+		p.accept("column")
+		from, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("to"); err != nil {
+			return err
+		}
+		to, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if c := t.column(from); c != nil {
+			c.name = to
+		}
+
+	case p.accept("alter"):
+		p.accept("column")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		c := t.column(name)
+		if c == nil {
+			return p.errorf("table %q has no column %q", t.name, name)
+		}
+		return p.alterColumn(c)
+	}
+
+	return nil
+}
+
+func (p *parser) alterColumn(c *column) error {
+	switch {
+	case p.accept("set", "not", "null"):
+		c.notNull = true
+	case p.accept("drop", "not", "null"):
+		c.notNull = false
+	case p.accept("set", "default"):
+		c.hasDefault = true
+	case p.accept("drop", "default"):
+		c.hasDefault = false
+	case p.accept("set", "data", "type"), p.accept("type"):
+		c.dataType = typeName(p.skip(func(t token) bool {
+			return t.is(",") || t.is("collate") || t.is("using")
+		}))
+	case p.accept("add", "generated"):
+		if p.accept("by", "default") {
+			c.hasDefault = true
+		} else {
+			c.generated = true
+		}
+	}
+	return nil
+}
+
+func (p *parser) dropTable(s *schema) error {
+	p.accept("if", "exists")
+
+	for {
+		schemaName, name, err := p.qualifiedIdent()
+		if err != nil {
+			return err
+		}
+
+		for i, t := range s.tables {
+			if t.name == name && sameSchema(t.schema, schemaName) {
+				s.tables = append(s.tables[:i], s.tables[i+1:]...)
+				break
+			}
+		}
+
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const pgDump = `
+--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE TYPE public.mood AS ENUM ('sad', 'ok', 'happy');
+
+CREATE FUNCTION public.touch() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.updated_at := now(); -- CREATE TABLE fake (id int);
+    RETURN NEW;
+END;
+$$;
+
+CREATE TABLE public.users (
+    id bigint NOT NULL,
+    email character varying(255) NOT NULL,
+    "Display Name" text,
+    balance numeric(10,2) DEFAULT 0.00 NOT NULL,
+    current_mood public.mood,
+    tags text[],
+    created_at timestamp(3) with time zone DEFAULT now() NOT NULL,
+    search tsvector GENERATED ALWAYS AS (to_tsvector('english', email)) STORED
+);
+
+/* Comments /* can be nested */ in PostgreSQL. */
+CREATE TABLE analytics.events (
+    id integer GENERATED BY DEFAULT AS IDENTITY,
+    user_id bigint REFERENCES public.users (id) ON DELETE SET NULL,
+    name text NOT NULL CHECK (name <> ''),
+    CONSTRAINT events_name_key UNIQUE (name)
+);
+
+CREATE SEQUENCE public.users_id_seq START WITH 1;
+ALTER TABLE public.users OWNER TO app;
+ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+CREATE INDEX users_email_idx ON public.users USING btree (email);
+`
+
+func TestParsePgDump(t *testing.T) {
+	s := &schema{}
+	if err := s.parse(pgDump); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []*table{
+		{
+			schema: "public",
+			name:   "users",
+			columns: []*column{
+				{name: "id", dataType: "bigint", notNull: true, primaryKey: true, hasDefault: true},
+				{name: "email", dataType: "character varying(255)", notNull: true},
+				{name: "Display Name", dataType: "text"},
+				{name: "balance", dataType: "numeric(10,2)", notNull: true, hasDefault: true},
+				{name: "current_mood", dataType: "public.mood"},
+				{name: "tags", dataType: "text[]"},
+				{name: "created_at", dataType: "timestamp(3) with time zone", notNull: true, hasDefault: true},
+				{name: "search", dataType: "tsvector", generated: true},
+			},
+		},
+		{
+			schema: "analytics",
+			name:   "events",
+			columns: []*column{
+				{name: "id", dataType: "integer", hasDefault: true},
+				{name: "user_id", dataType: "bigint"},
+				{name: "name", dataType: "text", notNull: true, unique: true},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(s.tables, expected) {
+		for i, tbl := range s.tables {
+			t.Logf("table %d: %+v", i, *tbl)
+			for _, c := range tbl.columns {
+				t.Logf("  %+v", *c)
+			}
+		}
+		t.Errorf("parsed schema does not match")
+	}
+}
+
+func TestParseMigrations(t *testing.T) {
+	migrations := []string{
+		`CREATE TABLE users (id serial PRIMARY KEY, name text, email text);
+		 CREATE TABLE IF NOT EXISTS sessions (token text);`,
+		`ALTER TABLE users ADD COLUMN age integer NOT NULL DEFAULT 0, DROP COLUMN email;
+		 ALTER TABLE users RENAME COLUMN name TO full_name;`,
+		`ALTER TABLE users ALTER COLUMN age TYPE bigint, ALTER COLUMN age DROP NOT NULL;
+		 ALTER TABLE sessions RENAME TO user_sessions;
+		 DROP TABLE IF EXISTS user_sessions CASCADE;`,
+	}
+
+	s := &schema{}
+	for i, m := range migrations {
+		if err := s.parse(m); err != nil {
+			t.Fatalf("migration %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	expected := []*table{
+		{
+			name: "users",
+			columns: []*column{
+				{name: "id", dataType: "serial", notNull: true, primaryKey: true, hasDefault: true},
+				{name: "full_name", dataType: "text"},
+				{name: "age", dataType: "bigint", hasDefault: true},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(s.tables, expected) {
+		for i, tbl := range s.tables {
+			t.Logf("table %d: %+v", i, *tbl)
+			for _, c := range tbl.columns {
+				t.Logf("  %+v", *c)
+			}
+		}
+		t.Errorf("parsed schema does not match")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		src string
+		err string
+	}{
+		{
+			"CREATE TABLE users (id int,\n name text, 'oops');",
+			"line 2: expected an identifier, found \"oops\"",
+		},
+		{
+			"CREATE TABLE users (id int, PRIMARY KEY (uid));",
+			`line 1: primary key of table "users" refers to unknown column "uid"`,
+		},
+		{
+			"CREATE TABLE users (\nname text\n",
+			`line 2: expected , or ) in definition of table "users", found end of statement`,
+		},
+		{
+			"CREATE TABLE \"users (id int);",
+			"line 1: unterminated quoted string",
+		},
+	}
+
+	for i, tc := range cases {
+		err := (&schema{}).parse(tc.src)
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package psql
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Eq returns an Expression representing the equality comparison between a and b.
 func Eq(a, b Expression) comparison {
@@ -35,6 +38,134 @@ func GreaterThanOrEq(a, b Expression) comparison {
 	return comparison{a, b, gte}
 }
 
+// Like returns an Expression representing the case-sensitive match of a
+// against the LIKE pattern b, in which % matches any sequence of characters
+// and _ matches any single character. Patterns built from user input should
+// be escaped with EscapeLike, or matched with Contains, StartsWith or
+// EndsWith instead.
+func Like(a, b Expression) comparison {
+	return comparison{a, b, like}
+}
+
+// NotLike returns the negation of Like(a, b).
+func NotLike(a, b Expression) comparison {
+	return comparison{a, b, notLike}
+}
+
+// ILike returns an Expression representing the case-insensitive match of a
+// against the LIKE pattern b.
+func ILike(a, b Expression) comparison {
+	return comparison{a, b, iLike}
+}
+
+// NotILike returns the negation of ILike(a, b).
+func NotILike(a, b Expression) comparison {
+	return comparison{a, b, notILike}
+}
+
+// Matches returns an Expression representing the case-sensitive match of a
+// against the POSIX regular expression b, using the ~ operator.
+func Matches(a, b Expression) comparison {
+	return comparison{a, b, regexMatch}
+}
+
+// MatchesFold returns an Expression representing the case-insensitive match
+// of a against the POSIX regular expression b, using the ~* operator.
+func MatchesFold(a, b Expression) comparison {
+	return comparison{a, b, regexIMatch}
+}
+
+// NotMatches returns the negation of Matches(a, b), using the !~ operator.
+func NotMatches(a, b Expression) comparison {
+	return comparison{a, b, notRegexMatch}
+}
+
+// SimilarTo returns an Expression representing the match of a against the
+// SQL regular expression b, using SIMILAR TO.
+func SimilarTo(a, b Expression) comparison {
+	return comparison{a, b, similarTo}
+}
+
+// NotSimilarTo returns the negation of SimilarTo(a, b).
+func NotSimilarTo(a, b Expression) comparison {
+	return comparison{a, b, notSimilarTo}
+}
+
+// NotMatchesFold returns the negation of MatchesFold(a, b), using the !~*
+// operator.
+func NotMatchesFold(a, b Expression) comparison {
+	return comparison{a, b, notRegexIMatch}
+}
+
+// likeEscaper escapes the characters that have a special meaning in LIKE
+// patterns, using the escape character in likeEscape.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// likeEscape is the ESCAPE clause appended to patterns built by Contains,
+// StartsWith and EndsWith. It avoids backslash, the default escape
+// character, whose meaning in a string literal depends on the setting of
+// standard_conforming_strings.
+const likeEscape = "ESCAPE '!'"
+
+// EscapeLike escapes the characters %, _ and ! in s with an exclamation
+// mark, so that s can be used as part of a LIKE or ILIKE pattern with the
+// clause ESCAPE '!', in which it only matches itself.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Contains returns an Expression that is true if expr contains the string
+// s, compared case-sensitively. Any % or _ in s are escaped, and s is passed
+// to the database as a parameter.
+func Contains(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral("%" + EscapeLike(s) + "%"), like}}
+}
+
+// StartsWith returns an Expression that is true if expr starts with the
+// string s, compared case-sensitively.
+func StartsWith(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral(EscapeLike(s) + "%"), like}}
+}
+
+// EndsWith returns an Expression that is true if expr ends with the string
+// s, compared case-sensitively.
+func EndsWith(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral("%" + EscapeLike(s)), like}}
+}
+
+// ContainsFold is like Contains, but compares case-insensitively using
+// ILIKE.
+func ContainsFold(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral("%" + EscapeLike(s) + "%"), iLike}}
+}
+
+// StartsWithFold is like StartsWith, but compares case-insensitively using
+// ILIKE.
+func StartsWithFold(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral(EscapeLike(s) + "%"), iLike}}
+}
+
+// EndsWithFold is like EndsWith, but compares case-insensitively using
+// ILIKE.
+func EndsWithFold(expr Expression, s string) patternMatch {
+	return patternMatch{comparison{expr, StringLiteral("%" + EscapeLike(s)), iLike}}
+}
+
+// patternMatch is a LIKE or ILIKE comparison whose pattern was escaped with
+// EscapeLike, and which is rendered with an explicit ESCAPE clause.
+type patternMatch struct {
+	comparison
+}
+
+func (m patternMatch) ToSQLBoolean(p *Params) string {
+	return m.ToSQLExpr(p)
+}
+
+func (m patternMatch) ToSQLExpr(p *Params) string {
+	p.check(m.compType)
+	return fmt.Sprintf("(%s %s %s %s)", p.Expr("left", m.a), m.compType, p.Expr("right", m.b), likeEscape)
+}
+
 type comparison struct {
 	a, b     Expression
 	compType comparisonType
@@ -45,7 +176,23 @@ func (c comparison) ToSQLBoolean(p *Params) string {
 }
 
 func (c comparison) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("(%s %s %s)", c.a.ToSQLExpr(p), c.compType, c.b.ToSQLExpr(p))
+	p.check(c.compType)
+	left := p.Expr("left", c.a)
+
+	var right string
+	if q, ok := c.b.(quantified); ok {
+		p.enter("right")
+		switch c.compType {
+		case distinctFrom, notDistinctFrom, similarTo, notSimilarTo:
+			p.Errorf("%s cannot be used with %s", q.quantType, c.compType)
+		}
+		right = q.toSQL(p)
+		p.leave()
+	} else {
+		right = p.Expr("right", c.b)
+	}
+
+	return fmt.Sprintf("(%s %s %s)", left, c.compType, right)
 }
 
 func (c comparison) Relations() []string {
@@ -64,6 +211,18 @@ const (
 	lte
 	gt
 	gte
+	like
+	notLike
+	iLike
+	notILike
+	regexMatch
+	regexIMatch
+	notRegexMatch
+	notRegexIMatch
+	similarTo
+	notSimilarTo
+	distinctFrom
+	notDistinctFrom
 )
 
 func (c comparisonType) String() string {
@@ -80,41 +239,311 @@ func (c comparisonType) String() string {
 		return ">"
 	case gte:
 		return ">="
+	case like:
+		return "LIKE"
+	case notLike:
+		return "NOT LIKE"
+	case iLike:
+		return "ILIKE"
+	case notILike:
+		return "NOT ILIKE"
+	case regexMatch:
+		return "~"
+	case regexIMatch:
+		return "~*"
+	case notRegexMatch:
+		return "!~"
+	case notRegexIMatch:
+		return "!~*"
+	case similarTo:
+		return "SIMILAR TO"
+	case notSimilarTo:
+		return "NOT SIMILAR TO"
+	case distinctFrom:
+		return "IS DISTINCT FROM"
+	case notDistinctFrom:
+		return "IS NOT DISTINCT FROM"
+	default:
+		return unknownEnum(c)
+	}
+}
+
+func (c comparisonType) valid() bool {
+	return c >= eq && c <= notDistinctFrom
+}
+
+// Any returns an Expression that, when used as the right operand of a
+// comparison, makes the comparison true if it holds for any element of
+// array, as in ("tag" = ANY ($1::text[])). If array is empty, the
+// comparison is false.
+func Any(array Expression) quantified {
+	return quantified{quantType: anyQuantifier, array: array}
+}
+
+// All returns an Expression that, when used as the right operand of a
+// comparison, makes the comparison true if it holds for every element of
+// array. If array is empty, the comparison is true.
+func All(array Expression) quantified {
+	return quantified{quantType: allQuantifier, array: array}
+}
+
+// Some is a synonym for Any.
+func Some(array Expression) quantified {
+	return quantified{quantType: someQuantifier, array: array}
+}
+
+// AnySelect is like Any, but compares against each row returned by the
+// subquery q, which must select a single column. In a correlated subquery,
+// columns of the outer query must be referred to with Outer.
+func AnySelect(q SelectQuery) quantified {
+	return quantified{quantType: anyQuantifier, query: &q}
+}
+
+// AllSelect is like All, but compares against each row returned by the
+// subquery q, which must select a single column.
+func AllSelect(q SelectQuery) quantified {
+	return quantified{quantType: allQuantifier, query: &q}
+}
+
+// SomeSelect is a synonym for AnySelect.
+func SomeSelect(q SelectQuery) quantified {
+	return quantified{quantType: someQuantifier, query: &q}
+}
+
+// quantified is the right operand of a quantified comparison, which is
+// either an array expression or a subquery. It is only valid SQL as part
+// of a comparison, which renders it with toSQL.
+type quantified struct {
+	quantType quantifierType
+	array     Expression
+	query     *SelectQuery
+}
+
+func (q quantified) ToSQLExpr(p *Params) string {
+	p.Errorf("%s can only be used as the right operand of a comparison", q.quantType)
+	return q.toSQL(p)
+}
+
+func (q quantified) toSQL(p *Params) string {
+	p.check(q.quantType)
+
+	if q.query != nil {
+		p.enter("subquery")
+		if exprs := q.query.sel.exprs; len(exprs) != 1 {
+			p.Errorf("the subquery of %s must select exactly one column, got %d", q.quantType, len(exprs))
+		} else if _, ok := exprs[0].(allColumns); ok {
+			p.Errorf("the subquery of %s must select exactly one column, got %s", q.quantType, exprs[0].ToSQLExpr(p))
+		}
+		query := q.query.toSQL(p)
+		p.leave()
+		return fmt.Sprintf("%s (%s)", q.quantType, query)
+	}
+
+	return fmt.Sprintf("%s (%s)", q.quantType, p.Expr("array", q.array))
+}
+
+// Relations returns the relations of the array expression. A subquery
+// has its own FROM clause, so its relations are not part of the outer
+// query's.
+func (q quantified) Relations() []string {
+	if q.query != nil {
+		return nil
+	}
+	return q.array.Relations()
+}
+
+type quantifierType int
+
+const (
+	anyQuantifier quantifierType = iota
+	allQuantifier
+	someQuantifier
+)
+
+func (q quantifierType) String() string {
+	switch q {
+	case anyQuantifier:
+		return "ANY"
+	case allQuantifier:
+		return "ALL"
+	case someQuantifier:
+		return "SOME"
 	default:
-		panic("unknown comparisonType")
+		return unknownEnum(q)
 	}
 }
 
+func (q quantifierType) valid() bool {
+	return q >= anyQuantifier && q <= someQuantifier
+}
+
+// IsDistinctFrom returns an Expression representing the null-safe
+// inequality comparison between a and b. Unlike NotEq, it is true rather
+// than NULL when exactly one of a and b is NULL, and false when both are.
+func IsDistinctFrom(a, b Expression) comparison {
+	return comparison{a, b, distinctFrom}
+}
+
+// IsNotDistinctFrom returns an Expression representing the null-safe
+// equality comparison between a and b. Unlike Eq, it is true rather than
+// NULL when both a and b are NULL.
+func IsNotDistinctFrom(a, b Expression) comparison {
+	return comparison{a, b, notDistinctFrom}
+}
+
+// Between returns an Expression that is true when expr is greater than or
+// equal to low and less than or equal to high.
+func Between(expr, low, high Expression) between {
+	return between{expr, low, high, false, false}
+}
+
+// NotBetween returns the negation of Between(expr, low, high).
+func NotBetween(expr, low, high Expression) between {
+	return between{expr, low, high, true, false}
+}
+
+// BetweenSymmetric is like Between, but swaps low and high if low is
+// greater than high, so that the range is never empty.
+func BetweenSymmetric(expr, low, high Expression) between {
+	return between{expr, low, high, false, true}
+}
+
+// NotBetweenSymmetric returns the negation of BetweenSymmetric(expr, low,
+// high).
+func NotBetweenSymmetric(expr, low, high Expression) between {
+	return between{expr, low, high, true, true}
+}
+
+type between struct {
+	expr, low, high Expression
+	negated         bool
+	symmetric       bool
+}
+
+func (b between) ToSQLBoolean(p *Params) string {
+	return b.ToSQLExpr(p)
+}
+
+func (b between) ToSQLExpr(p *Params) string {
+	return fmt.Sprintf("(%s %s %s AND %s)", p.Expr("operand", b.expr), b.operator(), p.Expr("low", b.low), p.Expr("high", b.high))
+}
+
+func (b between) operator() string {
+	op := "BETWEEN"
+	if b.negated {
+		op = "NOT " + op
+	}
+	if b.symmetric {
+		op += " SYMMETRIC"
+	}
+	return op
+}
+
+func (b between) Relations() []string {
+	var rels []string
+	rels = append(rels, b.expr.Relations()...)
+	rels = append(rels, b.low.Relations()...)
+	rels = append(rels, b.high.Relations()...)
+	return rels
+}
+
 // IsNull returns an Expression comparing expr and NULL for equality.
-func IsNull(expr Expression) nullCheck {
-	return nullCheck{expr, false}
+func IsNull(expr Expression) isCheck {
+	return isCheck{expr, isNull, false}
 }
 
 // IsNotNull returns an Expression comparing expr and NULL for inequality.
-func IsNotNull(expr Expression) nullCheck {
-	return nullCheck{expr, true}
+func IsNotNull(expr Expression) isCheck {
+	return isCheck{expr, isNull, true}
+}
+
+// IsTrue returns an Expression that is true when the boolean expr is true,
+// and false when it is false or NULL.
+func IsTrue(expr Expression) isCheck {
+	return isCheck{expr, isTrue, false}
+}
+
+// IsNotTrue returns an Expression that is true when the boolean expr is
+// false or NULL.
+func IsNotTrue(expr Expression) isCheck {
+	return isCheck{expr, isTrue, true}
+}
+
+// IsFalse returns an Expression that is true when the boolean expr is
+// false, and false when it is true or NULL.
+func IsFalse(expr Expression) isCheck {
+	return isCheck{expr, isFalse, false}
 }
 
-type nullCheck struct {
-	expr    Expression
-	negated bool
+// IsNotFalse returns an Expression that is true when the boolean expr is
+// true or NULL.
+func IsNotFalse(expr Expression) isCheck {
+	return isCheck{expr, isFalse, true}
 }
 
-func (c nullCheck) ToSQLBoolean(p *Params) string {
+// IsUnknown returns an Expression that is true when the boolean expr is
+// NULL.
+func IsUnknown(expr Expression) isCheck {
+	return isCheck{expr, isUnknown, false}
+}
+
+// IsNotUnknown returns an Expression that is true when the boolean expr is
+// true or false.
+func IsNotUnknown(expr Expression) isCheck {
+	return isCheck{expr, isUnknown, true}
+}
+
+// isCheck is an IS [NOT] test, which unlike a comparison is never NULL.
+type isCheck struct {
+	expr     Expression
+	testType isTestType
+	negated  bool
+}
+
+func (c isCheck) ToSQLBoolean(p *Params) string {
 	return c.ToSQLExpr(p)
 }
 
-func (c nullCheck) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("%s %s", c.expr.ToSQLExpr(p), c.operator())
+func (c isCheck) ToSQLExpr(p *Params) string {
+	p.check(c.testType)
+	return fmt.Sprintf("%s %s", p.Expr("operand", c.expr), c.operator())
 }
 
-func (c nullCheck) operator() string {
+func (c isCheck) operator() string {
 	if c.negated {
-		return "IS NOT NULL"
+		return fmt.Sprintf("IS NOT %s", c.testType)
 	}
-	return "IS NULL"
+	return fmt.Sprintf("IS %s", c.testType)
 }
 
-func (c nullCheck) Relations() []string {
+func (c isCheck) Relations() []string {
 	return c.expr.Relations()
 }
+
+type isTestType int
+
+const (
+	isNull isTestType = iota
+	isTrue
+	isFalse
+	isUnknown
+)
+
+func (t isTestType) String() string {
+	switch t {
+	case isNull:
+		return "NULL"
+	case isTrue:
+		return "TRUE"
+	case isFalse:
+		return "FALSE"
+	case isUnknown:
+		return "UNKNOWN"
+	default:
+		return unknownEnum(t)
+	}
+}
+
+func (t isTestType) valid() bool {
+	return t >= isNull && t <= isUnknown
+}
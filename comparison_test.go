@@ -0,0 +1,246 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatternMatchSQL(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		sql   string
+		args  []interface{}
+	}{
+		{
+			Select(TableColumn("users", "name")).Where(
+				Like(TableColumn("users", "name"), StringLiteral("J%")),
+				NotILike(TableColumn("users", "email"), StringLiteral("%@example.com")),
+			),
+			`SELECT "name" FROM "users" WHERE ("name" LIKE $1::text) AND ("email" NOT ILIKE $2::text)`,
+			[]interface{}{"J%", "%@example.com"},
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Matches(TableColumn("users", "name"), StringParam()),
+				MatchesFold(TableColumn("users", "email"), StringParam()),
+				NotMatches(TableColumn("users", "name"), StringParam()),
+				NotMatchesFold(TableColumn("users", "email"), StringParam()),
+			),
+			`SELECT "name" FROM "users" WHERE ("name" ~ $1::text) AND ("email" ~* $2::text) AND ("name" !~ $3::text) AND ("email" !~* $4::text)`,
+			nil,
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				ContainsFold(TableColumn("products", "name"), `50%_off!\`),
+			),
+			`SELECT "name" FROM "products" WHERE ("name" ILIKE $1::text ESCAPE '!')`,
+			[]interface{}{`%50!%!_off!!\%`},
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				StartsWith(TableColumn("products", "sku"), "AB_"),
+				EndsWith(TableColumn("products", "name"), "100%"),
+			),
+			`SELECT "name" FROM "products" WHERE ("sku" LIKE $1::text ESCAPE '!') AND ("name" LIKE $2::text ESCAPE '!')`,
+			[]interface{}{`AB!_%`, `%100!%`},
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				SimilarTo(TableColumn("products", "sku"), StringLiteral("(AB|CD)[0-9]+")),
+				NotSimilarTo(TableColumn("products", "name"), StringParam()),
+			),
+			`SELECT "name" FROM "products" WHERE ("sku" SIMILAR TO $1::text) AND ("name" NOT SIMILAR TO $2::text)`,
+			[]interface{}{"(AB|CD)[0-9]+", nil},
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+
+		if tc.args != nil && !reflect.DeepEqual(st.Args, tc.args) {
+			t.Errorf("test case %d: expected args %v, got %v", i+1, tc.args, st.Args)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	cases := map[string]string{
+		"plain":      "plain",
+		"100%":       "100!%",
+		"snake_case": "snake!_case",
+		"wow!":       "wow!!",
+		`C:\dir`:     `C:\dir`,
+	}
+
+	for in, out := range cases {
+		if escaped := EscapeLike(in); escaped != out {
+			t.Errorf("EscapeLike(%q): expected %q, got %q", in, out, escaped)
+		}
+	}
+}
+
+func TestPredicateSQL(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		sql   string
+	}{
+		{
+			Select(TableColumn("users", "name")).Where(
+				Between(TableColumn("users", "age"), IntLiteral(18), IntParam()),
+				NotBetweenSymmetric(TableColumn("users", "created_at"), TimestampTZParam(), Now()),
+			),
+			`SELECT "name" FROM "users" WHERE ("age" BETWEEN 18 AND $1::integer) AND ("created_at" NOT BETWEEN SYMMETRIC $2::timestamptz AND now())`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Or(
+					NotBetween(TableColumn("users", "age"), IntLiteral(18), IntLiteral(65)),
+					BetweenSymmetric(TableColumn("users", "score"), IntLiteral(100), IntLiteral(0)),
+				),
+			),
+			`SELECT "name" FROM "users" WHERE (("age" NOT BETWEEN 18 AND 65) OR ("score" BETWEEN SYMMETRIC 100 AND 0))`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				IsDistinctFrom(TableColumn("users", "email"), StringParam()),
+				Not(IsNotDistinctFrom(TableColumn("users", "referrer_id"), TableColumn("users", "id"))),
+			),
+			`SELECT "name" FROM "users" WHERE ("email" IS DISTINCT FROM $1::text) AND (NOT ("referrer_id" IS NOT DISTINCT FROM "id"))`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Or(IsTrue(TableColumn("users", "admin")), IsNotFalse(TableColumn("users", "verified"))),
+				IsUnknown(TableColumn("users", "subscribed")),
+			),
+			`SELECT "name" FROM "users" WHERE ("admin" IS TRUE OR "verified" IS NOT FALSE) AND "subscribed" IS UNKNOWN`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				IsNotTrue(TableColumn("users", "banned")),
+				IsFalse(Eq(TableColumn("users", "country"), StringLiteral("GB"))),
+				IsNotUnknown(TableColumn("users", "subscribed")),
+			),
+			`SELECT "name" FROM "users" WHERE "banned" IS NOT TRUE AND ("country" = $1::text) IS FALSE AND "subscribed" IS NOT UNKNOWN`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+}
+
+func TestQuantifiedComparisonSQL(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		sql   string
+	}{
+		{
+			Select(TableColumn("posts", "title")).Where(
+				Eq(TableColumn("posts", "tag"), Any(StringArrayParam())),
+			),
+			`SELECT "title" FROM "posts" WHERE ("tag" = ANY ($1::text[]))`,
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				GreaterThan(
+					TableColumn("products", "price"),
+					AllSelect(Select(TableColumn("competitors", "price")).Where(Eq(TableColumn("competitors", "region"), StringParam()))),
+				),
+				NotEq(TableColumn("products", "sku"), AnySelect(Select(TableColumn("recalls", "sku")))),
+			),
+			`SELECT "name" FROM "products" WHERE ("price" > ALL (SELECT "price" FROM "competitors" WHERE ("region" = $1::text))) AND ("sku" <> ANY (SELECT "sku" FROM "recalls"))`,
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				LessThan(TableColumn("products", "price"), AllSelect(
+					Select(TableColumn("competitors", "price")).Where(
+						Eq(TableColumn("competitors", "sku"), TableColumn("products", "sku").Outer()),
+					),
+				)),
+			),
+			`SELECT "name" FROM "products" WHERE ("price" < ALL (SELECT "price" FROM "competitors" WHERE ("sku" = "products"."sku")))`,
+		},
+		{
+			Select(TableColumn("players", "name")).Where(
+				GreaterThanOrEq(IntParam(), Some(TableColumn("players", "scores"))),
+				ILike(TableColumn("players", "name"), All(StringArrayParam())),
+			),
+			`SELECT "name" FROM "players" WHERE ($1::integer >= SOME ("scores")) AND ("name" ILIKE ALL ($2::text[]))`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+}
+
+func TestQuantifiedComparisonErrors(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		err   string
+	}{
+		{
+			Select(Any(IntArrayParam())),
+			"psql: SELECT[0]: ANY can only be used as the right operand of a comparison",
+		},
+		{
+			Select(TableColumn("users", "name")).Where(Eq(All(IntArrayParam()), TableColumn("users", "id"))),
+			"psql: WHERE[0].left: ALL can only be used as the right operand of a comparison",
+		},
+		{
+			Select(TableColumn("users", "name")).Where(IsDistinctFrom(TableColumn("users", "id"), Some(IntArrayParam()))),
+			"psql: WHERE[0].right: SOME cannot be used with IS DISTINCT FROM",
+		},
+		{
+			Select(TableColumn("users", "name")).Where(NotSimilarTo(TableColumn("users", "name"), Any(StringArrayParam()))),
+			"psql: WHERE[0].right: ANY cannot be used with NOT SIMILAR TO",
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "id"), AnySelect(Select(TableColumn("admins", "id"), TableColumn("admins", "name")))),
+			),
+			"psql: WHERE[0].right.subquery: the subquery of ANY must select exactly one column, got 2",
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "id"), AllSelect(Select(AllColumns("admins")))),
+			),
+			`psql: WHERE[0].right.subquery: the subquery of ALL must select exactly one column, got "admins".*`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(Eq(TableColumn("users", "id"), quantified{quantType: quantifierType(7), array: IntArrayParam()})),
+			"psql: WHERE[0].right: unknown quantifierType(7)",
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.query.Build()
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,647 @@
+package psql
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// CopyFrom creates a new CopyStatement that loads rows sent by the client
+// into the given columns of the table, using COPY ... FROM STDIN. As with
+// Table, the name may be schema-qualified, such as "schema.table".
+//
+// Without any options, the SQL of the statement is the same as that built
+// by pq.CopyIn, so its rows can be sent with Exec.
+func CopyFrom(name string, columns ...string) CopyStatement {
+	return CopyStatement{table: parseTable(name), columns: columns, direction: copyFrom}
+}
+
+// CopyTo creates a new CopyStatement that sends the given columns of the
+// table to the client, using COPY ... TO STDOUT.
+func CopyTo(name string, columns ...string) CopyStatement {
+	return CopyStatement{table: parseTable(name), columns: columns, direction: copyTo}
+}
+
+// A CopyStatement represents a COPY statement exchanging data between a
+// table and the client, together with the options describing the format
+// of the data. Its NewEncoder and NewDecoder methods convert between Go
+// values and data in that format.
+//
+// The data itself is exchanged using PostgreSQL's COPY protocol, which
+// database/sql cannot expose: the statement built by a CopyStatement has to
+// be sent, together with the encoded data, over a connection that gives
+// access to the protocol, such as the CopyFrom and CopyTo methods of pgx's
+// pgconn.PgConn. The only exception is a COPY ... FROM STDIN without
+// options, whose rows pq can encode and send itself; see Exec.
+type CopyStatement struct {
+	table     table
+	columns   []string
+	direction copyDirection
+
+	format    CopyFormat
+	header    bool
+	null      *string
+	delimiter rune
+}
+
+// CopyFormat is the format of the data exchanged by a COPY statement.
+type CopyFormat int
+
+const (
+	TextFormat CopyFormat = iota
+	CSVFormat
+)
+
+func (f CopyFormat) String() string {
+	switch f {
+	case TextFormat:
+		return "text"
+	case CSVFormat:
+		return "csv"
+	default:
+		return unknownEnum(f)
+	}
+}
+
+func (f CopyFormat) valid() bool {
+	return f == TextFormat || f == CSVFormat
+}
+
+type copyDirection int
+
+const (
+	copyFrom copyDirection = iota
+	copyTo
+)
+
+// Format returns a copy of the CopyStatement c using the given format.
+func (c CopyStatement) Format(f CopyFormat) CopyStatement {
+	c.format = f
+	return c
+}
+
+// Header returns a copy of the CopyStatement c whose data begins with a
+// line containing the names of the columns.
+func (c CopyStatement) Header() CopyStatement {
+	c.header = true
+	return c
+}
+
+// Null returns a copy of the CopyStatement c that represents NULL values
+// with the string s. The default is \N in text format, and an unquoted
+// empty string in CSV format.
+func (c CopyStatement) Null(s string) CopyStatement {
+	c.null = &s
+	return c
+}
+
+// Delimiter returns a copy of the CopyStatement c that separates columns
+// with r. The default is a tab in text format, and a comma in CSV format.
+// The delimiter must be a single-byte character other than a newline, a
+// carriage return or a backslash; in text format, it also cannot be a
+// period, a letter or a digit, which have a meaning of their own.
+func (c CopyStatement) Delimiter(r rune) CopyStatement {
+	c.delimiter = r
+	return c
+}
+
+// ToSQL returns a string containing the SQL version of the CopyStatement.
+func (c CopyStatement) ToSQL() string {
+	p := newParams()
+	return c.toSQL(p)
+}
+
+// Build is like ToSQL, but returns the SQL as a Statement without any
+// arguments, and returns an error if the statement's options are invalid.
+func (c CopyStatement) Build() (Statement, error) {
+	p := newParams()
+	sql := c.toSQL(p)
+
+	if err := p.err(); err != nil {
+		return Statement{}, err
+	}
+
+	return p.statement(sql), nil
+}
+
+func (c CopyStatement) toSQL(p *Params) string {
+	p.check(c.format)
+
+	cols := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		cols[i] = pq.QuoteIdentifier(col)
+	}
+
+	sql := fmt.Sprintf("COPY %s", c.table)
+	if len(cols) > 0 {
+		sql = fmt.Sprintf("%s (%s)", sql, strings.Join(cols, ", "))
+	}
+
+	if c.direction == copyTo {
+		sql += " TO STDOUT"
+	} else {
+		sql += " FROM STDIN"
+	}
+
+	var opts []string
+	if c.format != TextFormat {
+		opts = append(opts, fmt.Sprintf("FORMAT %s", c.format))
+	}
+	if c.header {
+		opts = append(opts, "HEADER")
+	}
+	if c.delimiter != 0 {
+		if err := c.checkDelimiter(); err != nil {
+			p.Errorf("%v", err)
+		}
+		opts = append(opts, fmt.Sprintf("DELIMITER %s", pq.QuoteLiteral(string(c.delimiter))))
+	}
+	if c.null != nil {
+		opts = append(opts, fmt.Sprintf("NULL %s", pq.QuoteLiteral(*c.null)))
+	}
+
+	if len(opts) > 0 {
+		sql = fmt.Sprintf("%s WITH (%s)", sql, strings.Join(opts, ", "))
+	}
+
+	return sql
+}
+
+// checkDelimiter returns an error if the statement's delimiter is one that
+// PostgreSQL rejects.
+func (c CopyStatement) checkDelimiter() error {
+	d := c.delimiter
+	switch {
+	case d == 0:
+		return nil
+	case d > unicode.MaxASCII:
+		return fmt.Errorf("COPY delimiter %q must be a single one-byte character", d)
+	case d == '\n' || d == '\r':
+		return errors.New("COPY delimiter cannot be newline or carriage return")
+	case d == '\\':
+		return fmt.Errorf("COPY delimiter cannot be %q", d)
+	case c.format == TextFormat && (d == '.' || unicode.IsLetter(d) || unicode.IsDigit(d)):
+		return fmt.Errorf("COPY delimiter cannot be %q in text format", d)
+	}
+	return nil
+}
+
+// delim returns the byte that separates columns, which is only meaningful
+// if checkDelimiter returns nil.
+func (c CopyStatement) delim() byte {
+	if c.delimiter != 0 {
+		return byte(c.delimiter)
+	}
+	if c.format == CSVFormat {
+		return ','
+	}
+	return '\t'
+}
+
+func (c CopyStatement) nullString() string {
+	if c.null != nil {
+		return *c.null
+	}
+	if c.format == CSVFormat {
+		return ""
+	}
+	return `\N`
+}
+
+// Exec sends rows to the server using pq's support for COPY ... FROM STDIN,
+// which prepares the statement in tx and calls Exec with the values of each
+// row, and returns the number of rows copied. Since pq encodes the rows
+// itself, the CopyStatement c cannot have any options.
+func (c CopyStatement) Exec(ctx context.Context, tx *sql.Tx, rows [][]interface{}) (int64, error) {
+	if c.direction != copyFrom || c.format != TextFormat || c.header || c.null != nil || c.delimiter != 0 {
+		return 0, errors.New("psql: only a COPY ... FROM STDIN statement without options can be executed with Exec")
+	}
+
+	st, err := c.Build()
+	if err != nil {
+		return 0, err
+	}
+	query := st.SQL
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, newQueryError(query, err)
+	}
+	defer stmt.Close()
+
+	for i, row := range rows {
+		if len(c.columns) > 0 && len(row) != len(c.columns) {
+			return 0, fmt.Errorf("psql: row %d has %d values, expected %d", i, len(row), len(c.columns))
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return 0, newQueryError(query, err)
+		}
+	}
+
+	// Calling Exec without arguments flushes the rows and ends the COPY.
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, newQueryError(query, err)
+	}
+
+	return res.RowsAffected()
+}
+
+// NewEncoder returns a CopyEncoder that writes rows to w in the format of
+// the CopyStatement c, ready to be sent to the server by COPY ... FROM STDIN.
+// The data must be sent over a raw COPY transport: it cannot be passed to
+// Exec or to pq.CopyIn, which encode each row themselves.
+func (c CopyStatement) NewEncoder(w io.Writer) *CopyEncoder {
+	return &CopyEncoder{stmt: c, w: bufio.NewWriter(w)}
+}
+
+// A CopyEncoder converts rows of Go values into COPY text or CSV data.
+type CopyEncoder struct {
+	stmt    CopyStatement
+	w       *bufio.Writer
+	started bool
+}
+
+// Encode writes a single row, preceded by the header line if this is the
+// first row and the statement has a header. Each value is converted as it
+// would be by the database/sql package, and nil values become NULL.
+func (e *CopyEncoder) Encode(row ...interface{}) error {
+	if err := e.stmt.checkDelimiter(); err != nil {
+		return fmt.Errorf("psql: %v", err)
+	}
+
+	if !e.started {
+		e.started = true
+		if e.stmt.header {
+			header := make([]interface{}, len(e.stmt.columns))
+			for i, col := range e.stmt.columns {
+				header[i] = col
+			}
+			if err := e.Encode(header...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, val := range row {
+		if i > 0 {
+			e.w.WriteByte(e.stmt.delim())
+		}
+
+		field, null, err := copyValue(val)
+		if err != nil {
+			return fmt.Errorf("psql: cannot encode column %d: %v", i, err)
+		}
+
+		if null {
+			e.w.WriteString(e.stmt.nullString())
+		} else if e.stmt.format == CSVFormat {
+			e.w.WriteString(e.csvField(field))
+		} else {
+			e.w.WriteString(e.textField(field))
+		}
+	}
+
+	return e.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (e *CopyEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *CopyEncoder) textField(s string) string {
+	var b strings.Builder
+	delim := e.stmt.delim()
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case delim:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func (e *CopyEncoder) csvField(s string) string {
+	// Quote fields that could otherwise be mistaken for NULL or for the
+	// end-of-data marker, as well as those containing special characters.
+	needsQuotes := s == e.stmt.nullString() || s == `\.` ||
+		strings.ContainsAny(s, "\"\r\n") || strings.IndexByte(s, e.stmt.delim()) >= 0
+
+	if !needsQuotes {
+		return s
+	}
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// copyValue converts a Go value into its textual representation, or
+// reports that it is NULL.
+func copyValue(val interface{}) (string, bool, error) {
+	v, err := driver.DefaultParameterConverter.ConvertValue(val)
+	if err != nil {
+		return "", false, err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return v, false, nil
+	case []byte:
+		return `\x` + hex.EncodeToString(v), false, nil
+	case bool:
+		if v {
+			return "t", false, nil
+		}
+		return "f", false, nil
+	case int64:
+		return strconv.FormatInt(v, 10), false, nil
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN", false, nil
+		case math.IsInf(v, 1):
+			return "Infinity", false, nil
+		case math.IsInf(v, -1):
+			return "-Infinity", false, nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), false, nil
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999Z07:00"), false, nil
+	default:
+		return "", false, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// NewDecoder returns a CopyDecoder that reads rows from r, which must hold
+// data in the format of the CopyStatement c, such as the output of a
+// COPY ... TO STDOUT statement.
+func (c CopyStatement) NewDecoder(r io.Reader) *CopyDecoder {
+	return &CopyDecoder{stmt: c, r: bufio.NewReader(r)}
+}
+
+// A CopyDecoder converts COPY text or CSV data back into rows of values.
+type CopyDecoder struct {
+	stmt    CopyStatement
+	r       *bufio.Reader
+	started bool
+	line    int
+}
+
+// ErrCopyData is wrapped by the errors returned by a CopyDecoder when its
+// input is malformed.
+var ErrCopyData = errors.New("malformed COPY data")
+
+// Decode reads the next row, skipping the header line if the statement has
+// one. Each column is returned as a string, with Valid set to false if the
+// column is NULL. At the end of the input, Decode returns io.EOF.
+func (d *CopyDecoder) Decode() ([]sql.NullString, error) {
+	if err := d.stmt.checkDelimiter(); err != nil {
+		return nil, fmt.Errorf("psql: %v", err)
+	}
+
+	if !d.started {
+		d.started = true
+		if d.stmt.header {
+			if _, err := d.Decode(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if d.stmt.format == CSVFormat {
+		return d.decodeCSV()
+	}
+	return d.decodeText()
+}
+
+func (d *CopyDecoder) readLine() (string, error) {
+	line, err := d.r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	d.line++
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+func (d *CopyDecoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("psql: line %d: %w: %s", d.line, ErrCopyData, fmt.Sprintf(format, args...))
+}
+
+func (d *CopyDecoder) decodeText() ([]sql.NullString, error) {
+	line, err := d.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r")
+	if line == `\.` {
+		return nil, io.EOF
+	}
+
+	var row []sql.NullString
+	for _, field := range splitUnescaped(line, d.stmt.delim()) {
+		if field == d.stmt.nullString() {
+			row = append(row, sql.NullString{})
+			continue
+		}
+
+		s, err := unescapeText(field)
+		if err != nil {
+			return nil, d.errorf("%v", err)
+		}
+		row = append(row, sql.NullString{String: s, Valid: true})
+	}
+
+	return row, nil
+}
+
+// splitUnescaped splits a line of text-format data on every occurrence of
+// delim that isn't preceded by a backslash.
+func splitUnescaped(line string, delim byte) []string {
+	var fields []string
+	start := 0
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case delim:
+			fields = append(fields, line[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(fields, line[start:])
+}
+
+func unescapeText(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if i == len(s) {
+			return "", errors.New("trailing backslash")
+		}
+
+		switch c := s[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x':
+			// \xHH, with one or two hex digits.
+			j := i + 1
+			for j < len(s) && j < i+3 && isHex(s[j]) {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte(c)
+				continue
+			}
+			n, _ := strconv.ParseUint(s[i+1:j], 16, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			// \NNN, with one to three octal digits.
+			j := i
+			for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(s[i:j], 8, 16)
+			b.WriteByte(byte(n))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func (d *CopyDecoder) decodeCSV() ([]sql.NullString, error) {
+	line, err := d.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	if line == `\.` {
+		return nil, io.EOF
+	}
+
+	var row []sql.NullString
+	delim := d.stmt.delim()
+
+	for {
+		var field strings.Builder
+		quoted := false
+
+		if strings.HasPrefix(line, `"`) {
+			quoted = true
+			line = line[1:]
+
+			for {
+				i := strings.IndexByte(line, '"')
+				if i < 0 {
+					// The quoted field continues on the next line.
+					field.WriteString(line)
+					field.WriteByte('\n')
+
+					next, err := d.readLine()
+					if err == io.EOF {
+						return nil, d.errorf("unterminated quoted field")
+					} else if err != nil {
+						return nil, err
+					}
+					line = next
+					continue
+				}
+
+				field.WriteString(line[:i])
+				line = line[i+1:]
+
+				if strings.HasPrefix(line, `"`) {
+					field.WriteByte('"')
+					line = line[1:]
+					continue
+				}
+				break
+			}
+		}
+
+		i := strings.IndexByte(line, delim)
+		rest := line
+		if i >= 0 {
+			rest = line[:i]
+		}
+
+		if quoted && rest != "" && rest != "\r" {
+			return nil, d.errorf("unexpected characters after quoted field")
+		}
+		if !quoted {
+			rest = strings.TrimSuffix(rest, "\r")
+			field.WriteString(rest)
+		}
+
+		s := field.String()
+		if !quoted && s == d.stmt.nullString() {
+			row = append(row, sql.NullString{})
+		} else {
+			row = append(row, sql.NullString{String: s, Valid: true})
+		}
+
+		if i < 0 {
+			return row, nil
+		}
+		line = line[i+1:]
+	}
+}
@@ -0,0 +1,224 @@
+package psql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// A CopyStatement can be built like any other query.
+var _ Builder = CopyStatement{}
+
+func TestCopyStatementSQL(t *testing.T) {
+	cases := []struct {
+		stmt CopyStatement
+		sql  string
+	}{
+		{
+			CopyFrom("users", "name", "email"),
+			pq.CopyIn("users", "name", "email"),
+		},
+		{
+			CopyFrom("analytics.events", "id"),
+			pq.CopyInSchema("analytics", "events", "id"),
+		},
+		{
+			CopyFrom("users", "name", "email").Format(CSVFormat).Header(),
+			`COPY "users" ("name", "email") FROM STDIN WITH (FORMAT csv, HEADER)`,
+		},
+		{
+			CopyTo("users").Delimiter('|').Null("it's null"),
+			`COPY "users" TO STDOUT WITH (DELIMITER '|', NULL 'it''s null')`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.stmt.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+		} else if st.SQL != tc.sql || len(st.Args) != 0 {
+			t.Errorf("test case %d: expected %q, got %q with arguments %v", i+1, tc.sql, st.SQL, st.Args)
+		}
+
+		if sql := tc.stmt.ToSQL(); sql != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, sql)
+		}
+	}
+
+	errCases := []struct {
+		stmt CopyStatement
+		err  string
+	}{
+		{CopyTo("users").Delimiter('\n'), "psql: COPY delimiter cannot be newline or carriage return"},
+		{CopyTo("users").Delimiter('é'), `psql: COPY delimiter 'é' must be a single one-byte character`},
+		{CopyTo("users").Delimiter('\\'), `psql: COPY delimiter cannot be '\\'`},
+		{CopyTo("users").Delimiter('.'), "psql: COPY delimiter cannot be '.' in text format"},
+		{CopyTo("users").Delimiter('x'), "psql: COPY delimiter cannot be 'x' in text format"},
+		{CopyTo("users").Delimiter('7'), "psql: COPY delimiter cannot be '7' in text format"},
+	}
+
+	for i, tc := range errCases {
+		if _, err := tc.stmt.Build(); err == nil || err.Error() != tc.err {
+			t.Errorf("error case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+		if err := tc.stmt.NewEncoder(io.Discard).Encode("a", "b"); err == nil || err.Error() != tc.err {
+			t.Errorf("error case %d: expected encoding error %q, got %v", i+1, tc.err, err)
+		}
+	}
+
+	if _, err := CopyTo("users").Format(CSVFormat).Delimiter('.').Build(); err != nil {
+		t.Errorf("unexpected error for a CSV delimiter: %v", err)
+	}
+}
+
+func TestCopyStatementExec(t *testing.T) {
+	copied := 0
+	db, fake := openFakeDB(func(query string, args []driver.Value) (*fakeRows, error) {
+		if len(args) > 0 {
+			copied++
+			return &fakeRows{}, nil
+		}
+		return &fakeRows{values: make([][]driver.Value, copied)}, nil
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt := CopyFrom("users", "name", "email")
+	n, err := stmt.Exec(ctx, tx, [][]interface{}{
+		{"Joe", "joe@example.com"},
+		{"Jane", nil},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 2 {
+		t.Errorf("expected 2 rows copied, got %d", n)
+	}
+
+	sql := pq.CopyIn("users", "name", "email")
+	if want := []string{"BEGIN", sql, sql, sql}; !reflect.DeepEqual(fake.statements(), want) {
+		t.Errorf("expected %q, got %q", want, fake.statements())
+	}
+
+	if _, err := stmt.Format(CSVFormat).Exec(ctx, tx, nil); err == nil {
+		t.Error("expected an error for a statement with options, got nil")
+	}
+
+	if _, err := stmt.Exec(ctx, tx, [][]interface{}{{"Joe"}}); err == nil {
+		t.Error("expected an error for a row with too few values, got nil")
+	}
+}
+
+func TestCopyEncoding(t *testing.T) {
+	ts := time.Date(2017, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	rows := [][]interface{}{
+		{"Joe", 42, true, nil},
+		{"tab\there", 1.5, false, []byte{0xde, 0xad}},
+		{`back\slash`, int64(-1), ts, "line\nbreak"},
+		{"", "\"quoted\", with comma", `\.`, "\\N"},
+	}
+
+	cases := []struct {
+		stmt CopyStatement
+		data string
+	}{
+		{
+			CopyFrom("t", "a", "b", "c", "d"),
+			"Joe\t42\tt\t\\N\n" +
+				"tab\\there\t1.5\tf\t\\\\xdead\n" +
+				"back\\\\slash\t-1\t2017-01-02 03:04:05.6Z\tline\\nbreak\n" +
+				"\t\"quoted\", with comma\t\\\\.\t\\\\N\n",
+		},
+		{
+			CopyFrom("t", "a", "b", "c", "d").Format(CSVFormat).Header(),
+			"a,b,c,d\n" +
+				"Joe,42,t,\n" +
+				"tab\there,1.5,f,\\xdead\n" +
+				"back\\slash,-1,2017-01-02 03:04:05.6Z,\"line\nbreak\"\n" +
+				"\"\",\"\"\"quoted\"\", with comma\",\"\\.\",\\N\n",
+		},
+	}
+
+	want := [][]sql.NullString{
+		{{String: "Joe", Valid: true}, {String: "42", Valid: true}, {String: "t", Valid: true}, {}},
+		{{String: "tab\there", Valid: true}, {String: "1.5", Valid: true}, {String: "f", Valid: true}, {String: `\xdead`, Valid: true}},
+		{{String: `back\slash`, Valid: true}, {String: "-1", Valid: true}, {String: "2017-01-02 03:04:05.6Z", Valid: true}, {String: "line\nbreak", Valid: true}},
+		{{String: "", Valid: true}, {String: "\"quoted\", with comma", Valid: true}, {String: `\.`, Valid: true}, {String: `\N`, Valid: true}},
+	}
+
+	for i, tc := range cases {
+		var buf bytes.Buffer
+		enc := tc.stmt.NewEncoder(&buf)
+
+		for _, row := range rows {
+			if err := enc.Encode(row...); err != nil {
+				t.Fatalf("test case %d: unexpected error: %v", i+1, err)
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i+1, err)
+		}
+
+		if buf.String() != tc.data {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.data, buf.String())
+		}
+
+		dec := tc.stmt.NewDecoder(&buf)
+		var got [][]sql.NullString
+		for {
+			row, err := dec.Decode()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("test case %d: unexpected error: %v", i+1, err)
+			}
+			got = append(got, row)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestCopyDecodingErrors(t *testing.T) {
+	cases := []struct {
+		stmt CopyStatement
+		data string
+	}{
+		{CopyTo("t"), "trailing\\\n"},
+		{CopyTo("t").Format(CSVFormat), "\"unterminated\n"},
+		{CopyTo("t").Format(CSVFormat), "\"quoted\"junk,1\n"},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.stmt.NewDecoder(strings.NewReader(tc.data)).Decode()
+		if err == nil || err == io.EOF {
+			t.Errorf("test case %d: expected an error, got %v", i+1, err)
+		}
+	}
+
+	row, err := CopyTo("t").NewDecoder(strings.NewReader("a\\x41\\102\tb\n\\.\n")).Decode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []sql.NullString{{String: "aAB", Valid: true}, {String: "b", Valid: true}}; !reflect.DeepEqual(row, want) {
+		t.Errorf("expected %v, got %v", want, row)
+	}
+}
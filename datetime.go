@@ -4,19 +4,7 @@ import "fmt"
 
 // Now returns an Expression representing a call to the 0-arity date/time function now().
 func Now() fnCall {
-	return fnCall{"now"}
-}
-
-type fnCall struct {
-	name string
-}
-
-func (f fnCall) ToSQLExpr(*Params) string {
-	return fmt.Sprintf("%s()", f.name)
-}
-
-func (f fnCall) Relations() []string {
-	return nil
+	return fnCall{name: "now"}
 }
 
 // DatePart returns an Expression representing a call to the date/time
@@ -31,7 +19,8 @@ type datePart struct {
 }
 
 func (d datePart) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("date_part('%s', %s)", d.field, d.expr.ToSQLExpr(p))
+	p.check(d.field)
+	return fmt.Sprintf("date_part('%s', %s)", d.field, p.Expr("arg", d.expr))
 }
 
 func (d datePart) Relations() []string {
@@ -109,10 +98,14 @@ func (d DateField) String() string {
 	case YearField:
 		return "year"
 	default:
-		panic("unknown DateField")
+		return unknownEnum(d)
 	}
 }
 
+func (d DateField) valid() bool {
+	return d >= CenturyField && d <= YearField
+}
+
 // DateTrunc returns an Expression representing a call to the date/time
 // function date_trunc(), which truncates expr to the given precision.
 func DateTrunc(precision DatePrecision, expr Expression) dateTrunc {
@@ -125,7 +118,8 @@ type dateTrunc struct {
 }
 
 func (d dateTrunc) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("date_trunc('%s', %s)", d.precision, d.expr.ToSQLExpr(p))
+	p.check(d.precision)
+	return fmt.Sprintf("date_trunc('%s', %s)", d.precision, p.Expr("arg", d.expr))
 }
 
 func (d dateTrunc) Relations() []string {
@@ -179,6 +173,10 @@ func (d DatePrecision) String() string {
 	case MillenniumPrecision:
 		return "millennium"
 	default:
-		panic("unknown DatePrecision")
+		return unknownEnum(d)
 	}
 }
+
+func (d DatePrecision) valid() bool {
+	return d >= MicrosecondsPrecision && d <= MillenniumPrecision
+}
@@ -0,0 +1,56 @@
+package psql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A BuildError describes a problem found while building a query, such as
+// an unknown operator or an invalid combination of clauses.
+type BuildError struct {
+	// Path identifies the offending node within the query, such as
+	// "WHERE[1].operands[0].right". It is empty if the problem concerns
+	// the query as a whole.
+	Path string
+
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("psql: %v", e.Err)
+	}
+	return fmt.Sprintf("psql: %s: %v", e.Path, e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// BuildErrors is the error returned by Build when one or more problems
+// were found in a query.
+type BuildErrors []*BuildError
+
+func (e BuildErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// enum is implemented by the enumerated types used to build queries, such
+// as comparisonType, whose String methods are only meaningful for valid
+// values.
+type enum interface {
+	fmt.Stringer
+	valid() bool
+}
+
+// unknownEnum returns the string representation of an invalid value of
+// an enumerated type, such as "comparisonType(9)".
+func unknownEnum(e enum) string {
+	rv := reflect.ValueOf(e)
+	return fmt.Sprintf("%s(%d)", rv.Type().Name(), rv.Int())
+}
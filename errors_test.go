@@ -0,0 +1,90 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectQueryBuild(t *testing.T) {
+	query := Select(
+		TableColumn("users", "name"),
+	).Where(
+		Eq(TableColumn("users", "city"), StringParam()),
+	)
+
+	st, err := query.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := query.Compile(); !reflect.DeepEqual(st, want) {
+		t.Errorf("expected %+v, got %+v", want, st)
+	}
+}
+
+func TestSelectQueryBuildErrors(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		paths []string
+	}{
+		{
+			Select(
+				TableColumn("users", "name"),
+				binaryOp{IntLiteral(1), IntLiteral(2), binaryOpType(42)},
+			),
+			[]string{"SELECT[1]"},
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).Where(
+				Or(
+					Eq(TableColumn("users", "city"), StringParam()),
+					comparison{TableColumn("users", "height"), Plus(IntLiteral(1), freeParam{DataType{kind: typeKind(99)}}), comparisonType(42)},
+				),
+			),
+			[]string{"WHERE[0].operands[1]", "WHERE[0].operands[1].right.right"},
+		},
+		{
+			Select(
+				aggregateFunc{TableColumn("users", "height"), aggregationType(42)},
+				DatePart(DateField(-1), Now()),
+				DateTrunc(DatePrecision(42), Now()),
+			).OrderBy(
+				OrderExpression{expr: TableColumn("users", "name"), direction: orderDirection(42)},
+			),
+			[]string{"SELECT[0]", "SELECT[1]", "SELECT[2]", "ORDER BY[0]"},
+		},
+		{
+			Select(
+				Eq(Param("id", IntType()), Param("id", TextType())),
+			),
+			[]string{"SELECT[0].right"},
+		},
+		{
+			Select().Where(
+				IsNull(TableColumn("users", "name")),
+			),
+			[]string{""},
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.query.Build()
+
+		errs, ok := err.(BuildErrors)
+		if !ok {
+			t.Errorf("test case %d: expected BuildErrors, got %v", i+1, err)
+			continue
+		}
+
+		var paths []string
+		for _, e := range errs {
+			paths = append(paths, e.Path)
+		}
+
+		if !reflect.DeepEqual(paths, tc.paths) {
+			t.Errorf("test case %d: expected errors at %q, got %v", i+1, tc.paths, errs)
+		}
+	}
+}
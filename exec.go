@@ -0,0 +1,197 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// A Builder is a query that can be built into a Statement, such as a
+// SelectQuery, InsertQuery or UpdateQuery. A Statement is itself a Builder.
+type Builder interface {
+	Build() (Statement, error)
+}
+
+// An Execer executes statements that do not return rows. It is satisfied by
+// *sql.DB, *sql.Tx and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Build returns st itself, so that a Statement can be executed with Query,
+// QueryRow and Exec.
+func (st Statement) Build() (Statement, error) {
+	return st, nil
+}
+
+// Query builds q and executes it using db, replacing its free parameters
+// with inputs. Positional free parameters are replaced in order; named ones
+// are replaced by inputs created with sql.Named. Errors returned by the
+// database are wrapped in a *QueryError.
+func Query(ctx context.Context, db Queryer, q Builder, inputs ...interface{}) (*sql.Rows, error) {
+	st, args, err := bind(q, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, st.SQL, args...)
+	if err != nil {
+		return nil, newQueryError(st.SQL, err)
+	}
+
+	return rows, nil
+}
+
+// QueryRow is like Query, but only returns the first row in the result.
+// As with database/sql, any error is deferred until the Row is scanned.
+func QueryRow(ctx context.Context, db Queryer, q Builder, inputs ...interface{}) *Row {
+	st, args, err := bind(q, inputs)
+	if err != nil {
+		return &Row{err: err}
+	}
+
+	rows, err := db.QueryContext(ctx, st.SQL, args...)
+	if err != nil {
+		return &Row{err: newQueryError(st.SQL, err)}
+	}
+
+	return &Row{rows: rows, sql: st.SQL}
+}
+
+// Exec builds q and executes it using db, replacing its free parameters
+// with inputs as Query does. Errors returned by the database are wrapped in
+// a *QueryError.
+func Exec(ctx context.Context, db Execer, q Builder, inputs ...interface{}) (sql.Result, error) {
+	st, args, err := bind(q, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.ExecContext(ctx, st.SQL, args...)
+	if err != nil {
+		return nil, newQueryError(st.SQL, err)
+	}
+
+	return res, nil
+}
+
+// bind builds q and returns the resulting statement along with its
+// arguments. If every input is an sql.NamedArg, they are bound by name;
+// otherwise, they are bound by position.
+func bind(q Builder, inputs []interface{}) (Statement, []interface{}, error) {
+	st, err := q.Build()
+	if err != nil {
+		return Statement{}, nil, err
+	}
+
+	named := make(map[string]interface{}, len(inputs))
+	for _, input := range inputs {
+		if arg, ok := input.(sql.NamedArg); ok {
+			named[arg.Name] = arg.Value
+		}
+	}
+
+	var args []interface{}
+	switch {
+	case len(named) == 0:
+		args, err = st.CheckedBindings(inputs...)
+	case len(named) == len(inputs):
+		args, err = st.BindNamed(named)
+	default:
+		err = errors.New("psql: named and positional inputs cannot be mixed")
+	}
+
+	return st, args, err
+}
+
+// Row is the result of calling QueryRow.
+type Row struct {
+	rows *sql.Rows
+	sql  string
+	err  error
+}
+
+// Scan copies the columns of the first row into dest, as sql.Row's Scan
+// method does. If there are no rows, Scan returns sql.ErrNoRows.
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return newQueryError(r.sql, err)
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	return r.rows.Close()
+}
+
+// Err returns the error, if any, that was encountered while running the
+// query. It is also returned by Scan.
+func (r *Row) Err() error {
+	return r.err
+}
+
+// A QueryError records an error returned while executing a statement,
+// together with the statement's SQL. When the error comes from PostgreSQL
+// via lib/pq, its most useful fields are copied into the QueryError.
+type QueryError struct {
+	SQL string
+	Err error
+
+	// Code is the SQLSTATE code of the error, such as "23505" for a
+	// unique_violation.
+	Code pq.ErrorCode
+
+	// Constraint, Table and Column name the database objects involved in
+	// the error, if any.
+	Constraint string
+	Table      string
+	Column     string
+
+	// Detail and Hint give further information about the error.
+	Detail string
+	Hint   string
+}
+
+func newQueryError(sql string, err error) *QueryError {
+	qe := &QueryError{SQL: sql, Err: err}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		qe.Code = pqErr.Code
+		qe.Constraint = pqErr.Constraint
+		qe.Table = pqErr.Table
+		qe.Column = pqErr.Column
+		qe.Detail = pqErr.Detail
+		qe.Hint = pqErr.Hint
+	}
+
+	return qe
+}
+
+func (e *QueryError) Error() string {
+	msg := fmt.Sprintf("psql: %v", e.Err)
+
+	// Newer versions of lib/pq already include the SQLSTATE code in the
+	// message of a *pq.Error, so only add it if it is missing.
+	if e.Code != "" && !strings.Contains(msg, string(e.Code)) {
+		msg += fmt.Sprintf(" (SQLSTATE %s)", e.Code)
+	}
+	return fmt.Sprintf("%s executing %q", msg, e.SQL)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
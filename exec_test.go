@@ -0,0 +1,182 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestQuery(t *testing.T) {
+	var gotArgs []driver.Value
+
+	db, fake := openFakeDB(func(_ string, args []driver.Value) (*fakeRows, error) {
+		gotArgs = args
+		return &fakeRows{
+			columns: []string{"name"},
+			values:  [][]driver.Value{{"Joe"}, {"Jane"}},
+		}, nil
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+
+	cases := []struct {
+		query  Builder
+		inputs []interface{}
+
+		sql  string
+		args []driver.Value
+	}{
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "id"), IntParam()),
+			),
+			[]interface{}{int64(42)},
+			`SELECT "name" FROM "users" WHERE ("id" = $1::integer)`,
+			[]driver.Value{int64(42)},
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "id"), Param("id", IntType())),
+				NotEq(TableColumn("users", "name"), StringLiteral("Jim")),
+			),
+			[]interface{}{sql.Named("id", int64(42))},
+			`SELECT "name" FROM "users" WHERE ("id" = $1::integer) AND ("name" <> $2::text)`,
+			[]driver.Value{int64(42), "Jim"},
+		},
+		{
+			Statement{SQL: "SELECT 1"},
+			nil,
+			"SELECT 1",
+			[]driver.Value{},
+		},
+	}
+
+	for i, tc := range cases {
+		rows, err := Query(ctx, db, tc.query, tc.inputs...)
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+		rows.Close()
+
+		stmts := fake.statements()
+		if sql := stmts[len(stmts)-1]; sql != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, sql)
+		}
+
+		if len(tc.args) == 0 && len(gotArgs) == 0 {
+			continue
+		}
+
+		if !reflect.DeepEqual(gotArgs, tc.args) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.args, gotArgs)
+		}
+	}
+
+	var name string
+	row := QueryRow(ctx, db, Select(TableColumn("users", "name")))
+	if err := row.Scan(&name); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if name != "Joe" {
+		t.Errorf("expected %q, got %q", "Joe", name)
+	}
+}
+
+func TestQueryBindingErrors(t *testing.T) {
+	db, fake := openFakeDB(nil)
+	defer db.Close()
+
+	ctx := context.Background()
+	query := Select(TableColumn("users", "name")).Where(
+		Eq(TableColumn("users", "id"), Param("id", IntType())),
+		Eq(TableColumn("users", "age"), IntParam()),
+	)
+
+	if _, err := Query(ctx, db, query, sql.Named("id", 1), 2); err == nil {
+		t.Error("expected an error for mixed inputs, got nil")
+	}
+
+	if err := QueryRow(ctx, db, Select().Where(Eq(IntLiteral(1), IntLiteral(1)))).Scan(); err == nil {
+		t.Error("expected a build error, got nil")
+	}
+
+	if _, err := Exec(ctx, db, Update("users").Set(Assignment{"age", IntParam()})); err == nil {
+		t.Error("expected an error for a missing binding, got nil")
+	}
+
+	if stmts := fake.statements(); len(stmts) > 0 {
+		t.Errorf("expected no statements to be executed, got %q", stmts)
+	}
+}
+
+func TestExec(t *testing.T) {
+	pqErr := &pq.Error{
+		Code:       "23505",
+		Message:    `duplicate key value violates unique constraint "users_email_key"`,
+		Detail:     "Key (email)=(joe@example.com) already exists.",
+		Table:      "users",
+		Constraint: "users_email_key",
+	}
+
+	fail := false
+	db, _ := openFakeDB(func(string, []driver.Value) (*fakeRows, error) {
+		if fail {
+			return nil, pqErr
+		}
+		return &fakeRows{values: make([][]driver.Value, 3)}, nil
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	query := Update("users").Set(Assignment{"email", "joe@example.com"})
+
+	res, err := Exec(ctx, db, query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n, _ := res.RowsAffected(); n != 3 {
+		t.Errorf("expected 3 rows affected, got %d", n)
+	}
+
+	fail = true
+	_, err = Exec(ctx, db, query)
+
+	var qe *QueryError
+	if !errors.As(err, &qe) {
+		t.Fatalf("expected a *QueryError, got %v", err)
+	}
+
+	expected := &QueryError{
+		SQL:        `UPDATE "users" SET "email" = $1`,
+		Err:        pqErr,
+		Code:       "23505",
+		Constraint: "users_email_key",
+		Table:      "users",
+		Detail:     "Key (email)=(joe@example.com) already exists.",
+	}
+
+	if !reflect.DeepEqual(qe, expected) {
+		t.Errorf("expected %+v, got %+v", expected, qe)
+	}
+
+	var wrapped *pq.Error
+	if !errors.As(err, &wrapped) || wrapped.Code != "23505" || wrapped.Message != pqErr.Message {
+		t.Errorf("expected the *QueryError to wrap %+v, got %+v", pqErr, wrapped)
+	}
+
+	if msg := qe.Error(); strings.Count(msg, "23505") != 1 || !strings.HasSuffix(msg, `executing "UPDATE \"users\" SET \"email\" = $1"`) {
+		t.Errorf("unexpected error message %q", msg)
+	}
+
+	if !errors.Is(err, pqErr) {
+		t.Error("expected the *QueryError to wrap the *pq.Error")
+	}
+}
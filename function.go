@@ -0,0 +1,59 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coalesce returns an Expression representing a call to COALESCE, whose
+// result is the first of exprs that is not NULL.
+func Coalesce(exprs ...Expression) fnCall {
+	return fnCall{name: "COALESCE", args: exprs, minArgs: 1}
+}
+
+// NullIf returns an Expression representing a call to NULLIF, whose result
+// is NULL if a is equal to b, and a otherwise.
+func NullIf(a, b Expression) fnCall {
+	return fnCall{name: "NULLIF", args: []Expression{a, b}}
+}
+
+// Greatest returns an Expression representing a call to GREATEST, whose
+// result is the largest of exprs, ignoring NULLs.
+func Greatest(exprs ...Expression) fnCall {
+	return fnCall{name: "GREATEST", args: exprs, minArgs: 1}
+}
+
+// Least returns an Expression representing a call to LEAST, whose result
+// is the smallest of exprs, ignoring NULLs.
+func Least(exprs ...Expression) fnCall {
+	return fnCall{name: "LEAST", args: exprs, minArgs: 1}
+}
+
+// fnCall is a call to the function with the given name, which takes
+// expressions as arguments.
+type fnCall struct {
+	name    string
+	args    []Expression
+	minArgs int
+}
+
+func (f fnCall) ToSQLExpr(p *Params) string {
+	if len(f.args) < f.minArgs {
+		p.Errorf("%s requires at least %d argument(s), got %d", f.name, f.minArgs, len(f.args))
+	}
+
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = p.Expr(fmt.Sprintf("args[%d]", i), arg)
+	}
+
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(args, ", "))
+}
+
+func (f fnCall) Relations() []string {
+	var rels []string
+	for _, arg := range f.args {
+		rels = append(rels, arg.Relations()...)
+	}
+	return rels
+}
@@ -0,0 +1,49 @@
+package psql
+
+import "testing"
+
+func TestFunctionSQL(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		sql   string
+	}{
+		{
+			Select(Coalesce(TableColumn("users", "nickname"), TableColumn("users", "name"), StringLiteral("anonymous"))),
+			`SELECT COALESCE("nickname", "name", $1::text) FROM "users"`,
+		},
+		{
+			Select(As(NullIf(TableColumn("orders", "discount"), IntLiteral(0)), "discount")),
+			`SELECT NULLIF("discount", 0) AS "discount" FROM "orders"`,
+		},
+		{
+			Select(
+				Greatest(TableColumn("users", "created_at"), TableColumn("orders", "created_at").Qualified()),
+				Least(TableColumn("users", "age"), IntParam()),
+			),
+			`SELECT GREATEST("created_at", "orders"."created_at"), LEAST("age", $1::integer) FROM "users", "orders"`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				GreaterThan(Coalesce(TableColumn("users", "updated_at"), Now()), TimestampTZParam()),
+			),
+			`SELECT "name" FROM "users" WHERE (COALESCE("updated_at", now()) > $1::timestamptz)`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+
+	_, err := Select(TableColumn("users", "name"), Coalesce()).Build()
+	if msg := "psql: SELECT[1]: COALESCE requires at least 1 argument(s), got 0"; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
@@ -0,0 +1,273 @@
+package psql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// MaxParams is the largest number of parameters that a single statement
+// can have, as limited by the PostgreSQL wire protocol.
+const MaxParams = 65535
+
+// Insert creates a new InsertQuery that adds rows to the table with the
+// given name, supplying values for the given columns. As with Table, the
+// name may be schema-qualified, such as "schema.table".
+func Insert(name string, columns ...string) InsertQuery {
+	return InsertQuery{
+		table:   parseTable(name),
+		columns: columns,
+	}
+}
+
+// An InsertQuery represents an INSERT statement whose rows come either from
+// a list of Go values or from a SELECT query.
+type InsertQuery struct {
+	table   table
+	columns []string
+	types   []DataType
+
+	rows  [][]interface{}
+	query *SelectQuery
+}
+
+// Types returns a copy of the InsertQuery q whose values are cast to the
+// given types, one for each column. Types are required by Unnest.
+func (q InsertQuery) Types(types ...DataType) InsertQuery {
+	q.types = types
+	return q
+}
+
+// Values returns a copy of the InsertQuery q with additional rows to be
+// inserted. Each row must hold one value for each of the query's columns.
+// Values that implement Expression are embedded in the query as such;
+// all other values are passed to the database as parameters.
+func (q InsertQuery) Values(rows ...[]interface{}) InsertQuery {
+	all := make([][]interface{}, len(q.rows), len(q.rows)+len(rows))
+	copy(all, q.rows)
+	q.rows = append(all, rows...)
+	return q
+}
+
+// Structs is like Values, but takes each row from an element of slice,
+// which must be a slice of structs or of pointers to structs. The value
+// of each column is taken from the struct field with the corresponding
+// `psql:"name"` tag. If the query was created without any columns, all
+// tagged fields are used, in order.
+func (q InsertQuery) Structs(slice interface{}) (InsertQuery, error) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return q, fmt.Errorf("psql: expected a slice of structs, got %T", slice)
+	}
+
+	rows := make([][]interface{}, rv.Len())
+	for i := range rows {
+		values, err := structValues(rv.Index(i).Interface())
+		if err != nil {
+			return q, err
+		}
+
+		if len(q.columns) == 0 {
+			sv, _ := structValue(rv.Index(i).Interface())
+			fields, _ := dominantFields(sv.Type())
+			for _, f := range fields {
+				q.columns = append(q.columns, f.name)
+			}
+		}
+
+		row := make([]interface{}, len(q.columns))
+		for j, col := range q.columns {
+			val, ok := values[col]
+			if !ok {
+				return q, fmt.Errorf("psql: element %d of the slice has no field tagged %q", i, col)
+			}
+			row[j] = val
+		}
+		rows[i] = row
+	}
+
+	return q.Values(rows...), nil
+}
+
+// Select returns a copy of the InsertQuery q that inserts the rows returned
+// by the SelectQuery s, rather than a list of values.
+func (q InsertQuery) Select(s SelectQuery) InsertQuery {
+	q.query = &s
+	return q
+}
+
+// ToSQL returns a string containing the full SQL version of the InsertQuery.
+func (q InsertQuery) ToSQL() string {
+	return q.Compile().SQL
+}
+
+// Compile converts the InsertQuery into a Statement. As with SelectQuery,
+// problems with the query are only reported by Build.
+func (q InsertQuery) Compile() Statement {
+	p := newParams()
+	return p.statement(q.toSQL(p, q.rows))
+}
+
+// Build is like Compile, but returns a BuildErrors describing every problem
+// found in the query, if any.
+func (q InsertQuery) Build() (Statement, error) {
+	p := newParams()
+	sql := q.toSQL(p, q.rows)
+
+	if err := p.err(); err != nil {
+		return Statement{}, err
+	}
+
+	return p.statement(sql), nil
+}
+
+// Batches splits the rows of the InsertQuery q into as few statements as
+// possible, such that none of them exceeds MaxParams parameters.
+func (q InsertQuery) Batches() ([]Statement, error) {
+	if q.query != nil {
+		return nil, errors.New("psql: cannot split an INSERT ... SELECT query into batches")
+	}
+
+	if len(q.columns) == 0 {
+		return nil, errors.New("psql: cannot insert rows without any columns")
+	}
+
+	var (
+		stmts  []Statement
+		start  int
+		params int
+	)
+
+	flush := func(end int) error {
+		p := newParams()
+		sql := q.toSQL(p, q.rows[start:end])
+		if err := p.err(); err != nil {
+			return err
+		}
+		stmts = append(stmts, p.statement(sql))
+		start, params = end, 0
+		return nil
+	}
+
+	for i, row := range q.rows {
+		n := q.rowParams(row)
+		if n > MaxParams {
+			return nil, fmt.Errorf("psql: row %d has %d parameters, more than can fit in a single statement", i, n)
+		}
+
+		if params+n > MaxParams {
+			if err := flush(i); err != nil {
+				return nil, err
+			}
+		}
+		params += n
+	}
+
+	if start < len(q.rows) {
+		if err := flush(len(q.rows)); err != nil {
+			return nil, err
+		}
+	}
+
+	return stmts, nil
+}
+
+// rowParams returns the number of parameters needed to insert row. Values
+// that implement Expression may need any number of parameters, including
+// none, so they are counted by rendering the row.
+func (q InsertQuery) rowParams(row []interface{}) int {
+	for _, val := range row {
+		if _, ok := val.(Expression); ok {
+			p := newParams()
+			q.toSQL(p, [][]interface{}{row})
+			return p.counter
+		}
+	}
+	return len(row)
+}
+
+// Unnest returns a single statement that inserts all rows of the InsertQuery
+// q regardless of their number, by passing the values of each column as an
+// array and expanding them with unnest(), such as:
+//
+//	INSERT INTO "users" ("id", "name") SELECT * FROM unnest($1::integer[], $2::text[])
+//
+// The type of every column must have been declared with Types, and values
+// cannot implement Expression, since they are passed to the database as
+// arrays rather than embedded in the query.
+func (q InsertQuery) Unnest() (Statement, error) {
+	if q.query != nil {
+		return Statement{}, errors.New("psql: cannot unnest an INSERT ... SELECT query")
+	}
+
+	if len(q.columns) == 0 || len(q.types) != len(q.columns) {
+		return Statement{}, fmt.Errorf("psql: unnest requires a type for each of the %d columns, got %d", len(q.columns), len(q.types))
+	}
+
+	cols := make([][]interface{}, len(q.columns))
+	for i, row := range q.rows {
+		if len(row) != len(q.columns) {
+			return Statement{}, fmt.Errorf("psql: row %d has %d values, expected %d", i, len(row), len(q.columns))
+		}
+		for j, val := range row {
+			if _, ok := val.(Expression); ok {
+				return Statement{}, fmt.Errorf("psql: row %d: the value of column %q is an Expression, which cannot be passed in an array", i, q.columns[j])
+			}
+			cols[j] = append(cols[j], val)
+		}
+	}
+
+	p := newParams()
+	arrays := make([]string, len(cols))
+	for j, col := range cols {
+		p.checkType(q.types[j])
+		arrays[j] = fmt.Sprintf("%s::%s", p.Add(pq.Array(col)), ArrayOf(q.types[j]))
+	}
+
+	sql := fmt.Sprintf("%s SELECT * FROM unnest(%s)", q.intoSQL(), strings.Join(arrays, ", "))
+	if err := p.err(); err != nil {
+		return Statement{}, err
+	}
+
+	return p.statement(sql), nil
+}
+
+func (q InsertQuery) toSQL(p *Params, rows [][]interface{}) string {
+	if q.query != nil {
+		if len(rows) > 0 {
+			p.Errorf("an INSERT query cannot have both a SELECT query and a list of values")
+		}
+
+		p.enter("SELECT")
+		defer p.leave()
+		return fmt.Sprintf("%s %s", q.intoSQL(), q.query.toSQL(p))
+	}
+
+	exprs := make([][]Expression, len(rows))
+	for i, row := range rows {
+		exprs[i] = make([]Expression, len(row))
+		for j, val := range row {
+			if expr, ok := val.(Expression); ok {
+				exprs[i][j] = expr
+			} else if j < len(q.types) {
+				exprs[i][j] = literal{val, q.types[j]}
+			} else {
+				exprs[i][j] = boundParam{val}
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s %s", q.intoSQL(), valuesList{rows: exprs}.toSQL(p, len(q.columns)))
+}
+
+func (q InsertQuery) intoSQL() string {
+	cols := make([]string, len(q.columns))
+	for i, col := range q.columns {
+		cols[i] = pq.QuoteIdentifier(col)
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s)", q.table, strings.Join(cols, ", "))
+}
@@ -0,0 +1,171 @@
+package psql
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestInsertQuerySQL(t *testing.T) {
+	type user struct {
+		ID    int    `psql:"id"`
+		Name  string `psql:"name"`
+		Email string
+	}
+
+	structs, err := Insert("users").Structs([]*user{{1, "Joe", ""}, {2, "Jane", ""}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		query InsertQuery
+
+		sql  string
+		args []interface{}
+	}{
+		{
+			Insert("users", "id", "name").Values(
+				[]interface{}{1, "Joe"},
+				[]interface{}{2, "Jane"},
+			),
+			`INSERT INTO "users" ("id", "name") VALUES ($1, $2), ($3, $4)`,
+			[]interface{}{1, "Joe", 2, "Jane"},
+		},
+		{
+			Insert("analytics.events", "id", "name").Types(IntType(), TextType()).Values(
+				[]interface{}{1, "signup"},
+			),
+			`INSERT INTO "analytics"."events" ("id", "name") VALUES ($1::integer, $2::text)`,
+			[]interface{}{1, "signup"},
+		},
+		{
+			structs,
+			`INSERT INTO "users" ("id", "name") VALUES ($1, $2), ($3, $4)`,
+			[]interface{}{1, "Joe", 2, "Jane"},
+		},
+		{
+			Insert("users", "name").Select(
+				Select(
+					TableColumn("v", "name"),
+				).From(
+					TypedValues([]DataType{TextType()}, []interface{}{"Joe"}).As("v", "name"),
+				),
+			),
+			`INSERT INTO "users" ("name") SELECT "name" FROM (VALUES ($1::text)) AS "v" ("name")`,
+			[]interface{}{"Joe"},
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+
+		if !reflect.DeepEqual(st.Args, tc.args) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.args, st.Args)
+		}
+	}
+}
+
+func TestInsertQueryBatches(t *testing.T) {
+	rows := make([][]interface{}, 50000)
+	for i := range rows {
+		rows[i] = []interface{}{i, "name", true}
+	}
+
+	query := Insert("users", "id", "name", "active").Values(rows...)
+
+	stmts, err := query.Batches()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stmts) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(stmts))
+	}
+
+	total := 0
+	for i, st := range stmts {
+		if len(st.Args) > MaxParams {
+			t.Errorf("statement %d has %d parameters", i+1, len(st.Args))
+		}
+		total += len(st.Args)
+	}
+
+	if total != 150000 {
+		t.Errorf("expected 150000 parameters in total, got %d", total)
+	}
+
+	if got := stmts[2].Args[0]; got != 43690 {
+		t.Errorf("expected the last batch to start at row 43690, got %v", got)
+	}
+}
+
+func TestInsertQueryBatchesExpressions(t *testing.T) {
+	rows := make([][]interface{}, 30000)
+	for i := range rows {
+		rows[i] = []interface{}{i, Coalesce(StringLiteral("nickname"), StringLiteral("name"))}
+	}
+
+	stmts, err := Insert("users", "id", "name").Values(rows...).Batches()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(stmts))
+	}
+
+	for i, st := range stmts {
+		if len(st.Args) > MaxParams {
+			t.Errorf("statement %d has %d parameters", i+1, len(st.Args))
+		}
+	}
+
+	if got := stmts[1].Args[0]; got != 21845 {
+		t.Errorf("expected the last batch to start at row 21845, got %v", got)
+	}
+}
+
+func TestInsertQueryUnnest(t *testing.T) {
+	query := Insert("users", "id", "name").Types(IntType(), TextType()).Values(
+		[]interface{}{1, "Joe"},
+		[]interface{}{2, "Jane"},
+	)
+
+	st, err := query.Unnest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `INSERT INTO "users" ("id", "name") SELECT * FROM unnest($1::integer[], $2::text[])`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	want := []interface{}{"{1,2}", `{"Joe","Jane"}`}
+	for i, arg := range st.Args {
+		val, err := arg.(driver.Valuer).Value()
+		if err != nil {
+			t.Errorf("arg %d: unexpected error: %v", i+1, err)
+		} else if val != want[i] {
+			t.Errorf("arg %d: expected %v, got %v", i+1, want[i], val)
+		}
+	}
+
+	if _, err := Insert("users", "id", "name").Unnest(); err == nil {
+		t.Error("expected an error for missing types, got nil")
+	}
+
+	_, err = Insert("users", "id", "name").Types(IntType(), TextType()).Values([]interface{}{1, StringLiteral("Joe")}).Unnest()
+	if msg := `psql: row 0: the value of column "name" is an Expression, which cannot be passed in an array`; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
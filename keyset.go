@@ -0,0 +1,176 @@
+package psql
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Cursor holds the values of a query's ORDER BY expressions for the
+// last row of a page of results, in the same order as they appear in
+// the ORDER BY clause. It can be passed to the After method of a
+// SelectQuery to fetch the rows that follow it.
+type Cursor []interface{}
+
+// Encode converts the Cursor c into an opaque token that is safe to
+// include in a URL, and that can be converted back with DecodeCursor.
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal([]interface{}(c))
+	if err != nil {
+		return "", fmt.Errorf("psql: cannot encode cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor converts a token created by Cursor.Encode back into a
+// Cursor. Since the token is encoded as JSON, numbers are decoded as
+// json.Number values and timestamps as strings; both are passed to the
+// database as text, and converted to the type of the corresponding ORDER
+// BY expression by PostgreSQL.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("psql: invalid cursor: %v", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var values []interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("psql: invalid cursor: %v", err)
+	}
+
+	return Cursor(values), nil
+}
+
+// After returns a copy of the SelectQuery s with an additional WHERE
+// condition that only matches the rows sorting after the row whose sort
+// key is held in cursor, according to the query's ORDER BY clause. This
+// makes it possible to paginate through results without using OFFSET,
+// also known as keyset or seek pagination.
+//
+// For the results to be correct, the expressions in the ORDER BY clause
+// must never be NULL, and must uniquely identify each row.
+func (s SelectQuery) After(cursor Cursor) (SelectQuery, error) {
+	keys := s.orderBy.exprs
+
+	if len(keys) == 0 {
+		return s, errors.New("psql: keyset pagination requires an ORDER BY clause")
+	}
+
+	if len(cursor) != len(keys) {
+		return s, fmt.Errorf("psql: cursor has %d values, but the ORDER BY clause has %d expressions", len(cursor), len(keys))
+	}
+
+	// Copy the existing conditions so that we don't modify the original
+	// query's backing array when appending to it.
+	conds := make([]BooleanExpression, len(s.where.exprs), len(s.where.exprs)+1)
+	copy(conds, s.where.exprs)
+
+	s.where = whereClause{append(conds, seek(keys, cursor))}
+	return s, nil
+}
+
+// seek returns a BooleanExpression that matches all rows sorting after
+// the given values. If all keys are sorted in the same direction, this
+// is a single row comparison, such as ("a", "b") > ($1, $2). Otherwise,
+// each key is compared in turn, such as ("a" > $1) OR (("a" = $2) AND
+// ("b" < $3)).
+func seek(keys []OrderExpression, values Cursor) BooleanExpression {
+	if sameDirection(keys) {
+		left := make(row, len(keys))
+		right := make(row, len(keys))
+		for i, key := range keys {
+			left[i] = sortKey{key}
+			right[i] = boundParam{values[i]}
+		}
+		return comparison{left, right, keys[0].direction.after()}
+	}
+
+	alts := make([]BooleanExpression, len(keys))
+	for i, key := range keys {
+		conds := make([]BooleanExpression, i+1)
+		for j := 0; j < i; j++ {
+			conds[j] = Eq(sortKey{keys[j]}, boundParam{values[j]})
+		}
+		conds[i] = comparison{sortKey{key}, boundParam{values[i]}, key.direction.after()}
+		alts[i] = And(conds...)
+	}
+
+	return Or(alts...)
+}
+
+func sameDirection(keys []OrderExpression) bool {
+	for _, key := range keys[1:] {
+		if key.direction != keys[0].direction {
+			return false
+		}
+	}
+	return true
+}
+
+// after returns the comparison operator that matches values sorting
+// after a given value in this direction.
+func (o orderDirection) after() comparisonType {
+	if o == desc {
+		return lt
+	}
+	return gt
+}
+
+// sortKey is an Expression representing the value an OrderExpression
+// sorts on, including its collation.
+type sortKey struct {
+	order OrderExpression
+}
+
+func (k sortKey) ToSQLExpr(p *Params) string {
+	return k.order.sortKey(p)
+}
+
+func (k sortKey) Relations() []string {
+	return k.order.Relations()
+}
+
+// row is an Expression representing a row constructor, such as ("a", "b").
+// A row with a single element is rendered as that element.
+type row []Expression
+
+func (r row) ToSQLExpr(p *Params) string {
+	if len(r) == 1 {
+		return p.Expr("fields[0]", r[0])
+	}
+
+	parts := make([]string, len(r))
+	for i, expr := range r {
+		parts[i] = p.Expr(fmt.Sprintf("fields[%d]", i), expr)
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+}
+
+func (r row) Relations() []string {
+	var rels []string
+	for _, expr := range r {
+		rels = append(rels, expr.Relations()...)
+	}
+	return rels
+}
+
+// boundParam is an Expression representing an untyped parameter whose
+// value is already known, leaving its type to be inferred by PostgreSQL.
+type boundParam struct {
+	value interface{}
+}
+
+func (b boundParam) ToSQLExpr(p *Params) string {
+	return p.Add(b.value)
+}
+
+func (boundParam) Relations() []string {
+	return nil
+}
@@ -0,0 +1,119 @@
+package psql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSelectQueryAfter(t *testing.T) {
+	base := Select(
+		TableColumn("posts", "title"),
+	).Where(
+		Eq(TableColumn("posts", "author"), StringParam()),
+	)
+
+	cases := []struct {
+		query  SelectQuery
+		cursor Cursor
+		inputs []interface{}
+
+		sql      string
+		bindings []interface{}
+	}{
+		{
+			base.OrderBy(
+				Ascending(TableColumn("posts", "id")),
+			),
+			Cursor{42},
+			[]interface{}{"Joe"},
+
+			`SELECT "title" FROM "posts" WHERE ("author" = $1::text) AND ("id" > $2) ORDER BY "id" ASC`,
+			[]interface{}{"Joe", 42},
+		},
+		{
+			base.OrderBy(
+				Descending(TableColumn("posts", "created_at")),
+				Descending(TableColumn("posts", "id")),
+			),
+			Cursor{"2017-01-01", 42},
+			[]interface{}{"Joe"},
+
+			`SELECT "title" FROM "posts" WHERE ("author" = $1::text) AND (("created_at", "id") < ($2, $3)) ORDER BY "created_at" DESC, "id" DESC`,
+			[]interface{}{"Joe", "2017-01-01", 42},
+		},
+		{
+			base.OrderBy(
+				Ascending(TableColumn("posts", "title")).Collate("C"),
+				Descending(TableColumn("posts", "id")),
+			),
+			Cursor{"Hello", 42},
+			[]interface{}{"Joe"},
+
+			`SELECT "title" FROM "posts" WHERE ("author" = $1::text) AND (("title" COLLATE "C" > $2) OR (("title" COLLATE "C" = $3) AND ("id" < $4))) ORDER BY "title" COLLATE "C" ASC, "id" DESC`,
+			[]interface{}{"Joe", "Hello", "Hello", 42},
+		},
+	}
+
+	for i, tc := range cases {
+		query, err := tc.query.After(tc.cursor)
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		sql := query.ToSQL()
+		if sql != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, sql)
+		}
+
+		bindings := query.Bindings(tc.inputs...)
+		if !reflect.DeepEqual(bindings, tc.bindings) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.bindings, bindings)
+		}
+	}
+
+	// The original query must not be affected by the additional condition.
+	want := `SELECT "title" FROM "posts" WHERE ("author" = $1::text)`
+	if got := base.ToSQL(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSelectQueryAfterErrors(t *testing.T) {
+	query := Select(TableColumn("posts", "title"))
+
+	if _, err := query.After(Cursor{42}); err == nil {
+		t.Error("expected an error for a query without ORDER BY, got nil")
+	}
+
+	query = query.OrderBy(Ascending(TableColumn("posts", "id")))
+
+	if _, err := query.After(Cursor{42, "Hello"}); err == nil {
+		t.Error("expected an error for a cursor of the wrong length, got nil")
+	}
+}
+
+func TestCursorEncoding(t *testing.T) {
+	ts := time.Date(2017, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	token, err := Cursor{ts, int64(9007199254740993), "Hello"}.Encode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cursor, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Cursor{"2017-01-02T03:04:05.000006Z", json.Number("9007199254740993"), "Hello"}
+	if !reflect.DeepEqual(cursor, want) {
+		t.Errorf("expected %v, got %v", want, cursor)
+	}
+
+	if _, err := DecodeCursor("not a cursor!"); err == nil {
+		t.Error("expected an error for an invalid token, got nil")
+	}
+}
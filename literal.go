@@ -0,0 +1,136 @@
+package psql
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// FloatLiteral returns a "double precision" literal that will be replaced
+// with f when the query is executed. Like StringLiteral, its value is
+// passed to the database as a parameter rather than interpolated.
+func FloatLiteral(f float64) literal {
+	return literal{f, FloatType()}
+}
+
+// BoolLiteral returns a "boolean" literal that will be replaced with b
+// when the query is executed. Unlike other literals, it is also a
+// BooleanExpression.
+func BoolLiteral(b bool) boolLiteral {
+	return boolLiteral{literal{b, BoolType()}}
+}
+
+type boolLiteral struct {
+	literal
+}
+
+func (b boolLiteral) ToSQLBoolean(p *Params) string {
+	return b.ToSQLExpr(p)
+}
+
+// TimeLiteral returns a "timestamptz" literal that will be replaced with
+// t when the query is executed.
+func TimeLiteral(t time.Time) literal {
+	return literal{t, TimestampTZType()}
+}
+
+// DurationLiteral returns an "interval" literal that will be replaced with
+// d when the query is executed. Since PostgreSQL intervals have microsecond
+// resolution, any fraction of a microsecond is truncated.
+func DurationLiteral(d time.Duration) literal {
+	return literal{fmt.Sprintf("%d microseconds", d/time.Microsecond), IntervalType()}
+}
+
+// BytesLiteral returns a "bytea" literal that will be replaced with b
+// when the query is executed.
+func BytesLiteral(b []byte) literal {
+	return literal{b, ByteaType()}
+}
+
+// UUIDLiteral returns a "uuid" literal that will be replaced with the UUID
+// whose textual representation is id when the query is executed, such as
+// "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11".
+func UUIDLiteral(id string) literal {
+	return literal{id, UUIDType()}
+}
+
+// numericScale is the number of decimal digits kept by DecimalLiteral when
+// a rational number cannot be represented exactly as a decimal.
+const numericScale = 20
+
+// DecimalLiteral returns a "numeric" literal that will be replaced with r
+// when the query is executed. If r has no exact decimal representation,
+// such as 1/3, it is rounded to 20 decimal digits.
+func DecimalLiteral(r *big.Rat) literal {
+	if r == nil {
+		return literal{nil, NumericType()}
+	}
+
+	return literal{r.FloatString(decimalScale(r)), NumericType()}
+}
+
+// decimalScale returns the number of decimal digits needed to represent r
+// exactly, or numericScale if there is no exact representation. A rational
+// number in lowest terms has one if and only if its denominator has no prime
+// factors other than 2 and 5, in which case the number of digits is the
+// larger of the number of times each of them occurs.
+func decimalScale(r *big.Rat) int {
+	d := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	q, m := new(big.Int), new(big.Int)
+
+	count := func(f *big.Int) int {
+		n := 0
+		for {
+			q.QuoRem(d, f, m)
+			if m.Sign() != 0 {
+				return n
+			}
+			d.Set(q)
+			n++
+		}
+	}
+
+	twos, fives := count(two), count(five)
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return numericScale
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
+
+type literal struct {
+	value    interface{}
+	dataType DataType
+}
+
+func (l literal) ToSQLExpr(p *Params) string {
+	p.checkType(l.dataType)
+	marker := p.Add(l.value)
+	return fmt.Sprintf("%s::%s", marker, l.dataType)
+}
+
+func (literal) Relations() []string {
+	return nil
+}
+
+// NullLiteral returns an Expression representing the NULL value of the
+// given type.
+func NullLiteral(t DataType) nullLiteral {
+	return nullLiteral{t}
+}
+
+type nullLiteral struct {
+	dataType DataType
+}
+
+func (n nullLiteral) ToSQLExpr(p *Params) string {
+	p.checkType(n.dataType)
+	return fmt.Sprintf("NULL::%s", n.dataType)
+}
+
+func (nullLiteral) Relations() []string {
+	return nil
+}
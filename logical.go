@@ -0,0 +1,111 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// And returns a BooleanExpression that is true when all of the given
+// BooleanExpressions are true. If no expressions are provided, the
+// result is the constant TRUE.
+func And(exprs ...BooleanExpression) logicalOp {
+	return logicalOp{exprs, and}
+}
+
+// Or returns a BooleanExpression that is true when at least one of the
+// given BooleanExpressions is true. If no expressions are provided, the
+// result is the constant FALSE.
+func Or(exprs ...BooleanExpression) logicalOp {
+	return logicalOp{exprs, or}
+}
+
+type logicalOp struct {
+	exprs  []BooleanExpression
+	opType logicalOpType
+}
+
+func (l logicalOp) ToSQLBoolean(p *Params) string {
+	return l.ToSQLExpr(p)
+}
+
+func (l logicalOp) ToSQLExpr(p *Params) string {
+	p.check(l.opType)
+
+	switch len(l.exprs) {
+	case 0:
+		return l.opType.identity()
+	case 1:
+		return p.Boolean("operands[0]", l.exprs[0])
+	}
+
+	parts := make([]string, len(l.exprs))
+	for i, expr := range l.exprs {
+		parts[i] = p.Boolean(fmt.Sprintf("operands[%d]", i), expr)
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(parts, fmt.Sprintf(" %s ", l.opType)))
+}
+
+func (l logicalOp) Relations() []string {
+	var rels []string
+	for _, expr := range l.exprs {
+		rels = append(rels, expr.Relations()...)
+	}
+	return rels
+}
+
+type logicalOpType int
+
+const (
+	and logicalOpType = iota
+	or
+)
+
+func (l logicalOpType) String() string {
+	switch l {
+	case and:
+		return "AND"
+	case or:
+		return "OR"
+	default:
+		return unknownEnum(l)
+	}
+}
+
+func (l logicalOpType) valid() bool {
+	return l == and || l == or
+}
+
+// identity returns the value of the operation when applied to an empty
+// list of operands.
+func (l logicalOpType) identity() string {
+	switch l {
+	case and:
+		return "TRUE"
+	case or:
+		return "FALSE"
+	default:
+		return l.String()
+	}
+}
+
+// Not returns a BooleanExpression representing the logical negation of expr.
+func Not(expr BooleanExpression) not {
+	return not{expr}
+}
+
+type not struct {
+	expr BooleanExpression
+}
+
+func (n not) ToSQLBoolean(p *Params) string {
+	return n.ToSQLExpr(p)
+}
+
+func (n not) ToSQLExpr(p *Params) string {
+	return fmt.Sprintf("(NOT %s)", p.Boolean("operand", n.expr))
+}
+
+func (n not) Relations() []string {
+	return n.expr.Relations()
+}
@@ -57,7 +57,8 @@ type binaryOp struct {
 }
 
 func (o binaryOp) ToSQLExpr(p *Params) string {
-	return fmt.Sprintf("(%s %s %s)", o.a.ToSQLExpr(p), o.opType, o.b.ToSQLExpr(p))
+	p.check(o.opType)
+	return fmt.Sprintf("(%s %s %s)", p.Expr("left", o.a), o.opType, p.Expr("right", o.b))
 }
 
 func (o binaryOp) Relations() []string {
@@ -93,6 +94,10 @@ func (b binaryOpType) String() string {
 	case pow:
 		return "^"
 	default:
-		panic("unknown binaryOpType")
+		return unknownEnum(b)
 	}
 }
+
+func (b binaryOpType) valid() bool {
+	return b >= plus && b <= pow
+}
@@ -0,0 +1,350 @@
+package psql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/lib/pq"
+)
+
+// A TableDef describes a database table whose columns correspond to the
+// tagged fields of a Go struct, so that column names only have to be spelled
+// out once, in the struct's tags:
+//
+//	type User struct {
+//		ID        int64     `psql:"id,pk"`
+//		Email     string    `psql:"email"`
+//		Settings  Settings  `psql:"settings,jsonb"`
+//		CreatedAt time.Time `psql:"created_at,readonly"`
+//	}
+//
+//	var users, _ = psql.DefineTable("users", User{})
+//
+// After the column name, a tag may contain the following options:
+//
+//	pk         the column is part of the primary key
+//	omitempty  the column is left out of assignments if its value is zero
+//	readonly   the column is never assigned, such as a generated column
+//	generated  the same as readonly
+//	jsonb      the value is encoded as JSON and sent as jsonb
+//	type=T     parameters for the column are cast to the type T, such as
+//	           uuid, numeric(10,2), text[] or a user-defined enum
+type TableDef struct {
+	table  table
+	typ    reflect.Type
+	fields []structField
+	byName map[string]int
+}
+
+// DefineTable returns a TableDef for the table with the given name, whose
+// columns are described by the tagged fields of model, a struct or a pointer
+// to a struct. As with Table, the name may be schema-qualified.
+func DefineTable(name string, model interface{}) (*TableDef, error) {
+	rv, err := structValue(model)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TableDef{
+		table:  parseTable(name),
+		typ:    rv.Type(),
+		fields: structFields(rv.Type()),
+		byName: make(map[string]int),
+	}
+
+	if len(t.fields) == 0 {
+		return nil, fmt.Errorf("psql: %s has no fields tagged with column names", t.typ)
+	}
+
+	columns := make(map[string]bool)
+	for i, f := range t.fields {
+		if f.name == "" {
+			return nil, fmt.Errorf("psql: field %s of %s has an empty column name", f.goName, t.typ)
+		}
+		if columns[f.name] {
+			return nil, fmt.Errorf("psql: column %q appears more than once in %s", f.name, t.typ)
+		}
+		columns[f.name] = true
+		t.byName[f.goName] = i
+	}
+
+	return t, nil
+}
+
+// Table returns the Relation represented by the TableDef t.
+func (t *TableDef) Table() Relation {
+	return t.table
+}
+
+// Column returns an Expression representing the column that corresponds
+// to the struct field with the given Go name. If there is no such field,
+// an error is reported when the query is built.
+func (t *TableDef) Column(field string) tableColumn {
+	i, ok := t.byName[field]
+	if !ok {
+		return tableColumn{
+			table:  t.table,
+			column: field,
+			err:    fmt.Sprintf("%s has no field %s tagged with a column name", t.typ, field),
+		}
+	}
+	return tableColumn{table: t.table, column: t.fields[i].name}
+}
+
+// Param returns a named free parameter for the column that corresponds to
+// the struct field with the given Go name. The parameter has the same name
+// as the column, so its value can be supplied by BindStruct, and its type
+// is given by the field's "type" option, as in `psql:"id,type=uuid"`, or
+// otherwise derived from the field's Go type. If neither is possible, the
+// parameter is not cast, and PostgreSQL infers its type from the context.
+func (t *TableDef) Param(field string) namedParam {
+	i, ok := t.byName[field]
+	if !ok {
+		return namedParam{
+			name: field,
+			err:  fmt.Sprintf("%s has no field %s tagged with a column name", t.typ, field),
+		}
+	}
+
+	f := t.fields[i]
+	dataType, err := f.dataType()
+	if err != nil {
+		return namedParam{
+			name: f.name,
+			err:  fmt.Sprintf("field %s of %s: %v", f.goName, t.typ, err),
+		}
+	}
+	return namedParam{name: f.name, dataType: dataType}
+}
+
+// Columns returns an Expression for each of the table's columns, in the
+// order of the struct's fields, for use as an explicit SELECT list.
+func (t *TableDef) Columns() []Expression {
+	exprs := make([]Expression, len(t.fields))
+	for i, f := range t.fields {
+		exprs[i] = tableColumn{table: t.table, column: f.name}
+	}
+	return exprs
+}
+
+// ColumnNames returns the names of the table's columns, in the order of
+// the struct's fields.
+func (t *TableDef) ColumnNames() []string {
+	names := make([]string, len(t.fields))
+	for i, f := range t.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// InsertAssignments returns the columns and values needed to insert the
+// struct v as a new row. Read-only columns are left out, as are columns
+// tagged omitempty whose value is zero.
+func (t *TableDef) InsertAssignments(v interface{}) ([]Assignment, error) {
+	return t.assignments(v, false)
+}
+
+// UpdateAssignments is like InsertAssignments, but also leaves out the
+// columns of the primary key, for use in the SET clause of an UPDATE.
+func (t *TableDef) UpdateAssignments(v interface{}) ([]Assignment, error) {
+	return t.assignments(v, true)
+}
+
+// Insert returns an InsertQuery adding the struct v to the table, using
+// the columns returned by InsertAssignments.
+func (t *TableDef) Insert(v interface{}) (InsertQuery, error) {
+	assignments, err := t.InsertAssignments(v)
+	if err != nil {
+		return InsertQuery{}, err
+	}
+
+	q := InsertQuery{table: t.table}
+	row := make([]interface{}, len(assignments))
+	for i, a := range assignments {
+		q.columns = append(q.columns, a.Column)
+		row[i] = a.Value
+	}
+
+	return q.Values(row), nil
+}
+
+// Update returns an UpdateQuery that sets the columns returned by
+// UpdateAssignments on the row whose primary key matches that of v.
+func (t *TableDef) Update(v interface{}) (UpdateQuery, error) {
+	assignments, err := t.UpdateAssignments(v)
+	if err != nil {
+		return UpdateQuery{}, err
+	}
+
+	rv, err := t.value(v)
+	if err != nil {
+		return UpdateQuery{}, err
+	}
+
+	var conds []BooleanExpression
+	for _, f := range t.fields {
+		if !f.hasOption("pk") {
+			continue
+		}
+
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			return UpdateQuery{}, fmt.Errorf("psql: primary key field %s of %s is unreachable", f.goName, t.typ)
+		}
+
+		value, err := t.fieldValue(f, fv)
+		if err != nil {
+			return UpdateQuery{}, err
+		}
+
+		expr, ok := value.(Expression)
+		if !ok {
+			expr = boundParam{value}
+		}
+		conds = append(conds, Eq(tableColumn{table: t.table, column: f.name}, expr))
+	}
+
+	if len(conds) == 0 {
+		return UpdateQuery{}, fmt.Errorf("psql: %s has no fields tagged as primary key", t.typ)
+	}
+
+	return UpdateQuery{table: t.table}.Set(assignments...).Where(conds...), nil
+}
+
+func (t *TableDef) value(v interface{}) (reflect.Value, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return rv, err
+	}
+
+	if rv.Type() != t.typ {
+		return rv, fmt.Errorf("psql: expected a %s, got %s", t.typ, rv.Type())
+	}
+
+	return rv, nil
+}
+
+func (t *TableDef) assignments(v interface{}, update bool) ([]Assignment, error) {
+	rv, err := t.value(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []Assignment
+	for _, f := range t.fields {
+		if f.hasOption("readonly") || f.hasOption("generated") || (update && f.hasOption("pk")) {
+			continue
+		}
+
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || (f.hasOption("omitempty") && fv.IsZero()) {
+			continue
+		}
+
+		value, err := t.fieldValue(f, fv)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, Assignment{f.name, value})
+	}
+
+	if len(assignments) == 0 {
+		return nil, errors.New("psql: no columns to assign")
+	}
+
+	return assignments, nil
+}
+
+// fieldValue returns the value to assign to the column of the field f, given
+// the field's value fv. It is a literal cast to the field's type if the type
+// is known, with slices for array types wrapped with pq.Array, and fv's
+// underlying value otherwise. Fields tagged jsonb are encoded as JSON.
+func (t *TableDef) fieldValue(f structField, fv reflect.Value) (interface{}, error) {
+	if f.hasOption("jsonb") {
+		data, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("psql: cannot encode field %s as JSON: %v", f.goName, err)
+		}
+		return literal{data, JSONBType()}, nil
+	}
+
+	dataType, err := f.dataType()
+	if err != nil {
+		return nil, fmt.Errorf("psql: field %s of %s: %v", f.goName, t.typ, err)
+	}
+
+	var value interface{} = fv.Interface()
+	if dataType.dims > 0 && fv.Kind() == reflect.Slice {
+		value = pq.Array(value)
+	}
+
+	if dataType == unknownType {
+		return value, nil
+	}
+	return literal{value, dataType}, nil
+}
+
+var (
+	nullStringType  = reflect.TypeOf(sql.NullString{})
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+	bytesType       = reflect.TypeOf([]byte(nil))
+)
+
+// dataType returns the PostgreSQL type named by the field's "type" option,
+// or else the one corresponding to the field's Go type, or unknownType if
+// there is none. It returns an error if the "type" option cannot be parsed.
+func (f structField) dataType() (DataType, error) {
+	if name, ok := f.option("type"); ok {
+		return parseDataType(name)
+	}
+
+	if f.hasOption("jsonb") {
+		return JSONBType(), nil
+	}
+
+	return goDataType(f.typ), nil
+}
+
+// goDataType returns the PostgreSQL type corresponding to the Go type typ,
+// or unknownType if there is none.
+func goDataType(typ reflect.Type) DataType {
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ {
+	case timeType:
+		return TimestampTZType()
+	case bytesType:
+		return ByteaType()
+	case nullStringType:
+		return TextType()
+	case nullInt64Type:
+		return BigIntType()
+	case nullFloat64Type:
+		return FloatType()
+	case nullBoolType:
+		return BoolType()
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return TextType()
+	case reflect.Bool:
+		return BoolType()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return IntType()
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return BigIntType()
+	case reflect.Float32, reflect.Float64:
+		return FloatType()
+	default:
+		return unknownType
+	}
+}
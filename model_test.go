@@ -0,0 +1,250 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type testSettings struct {
+	Theme string `json:"theme"`
+}
+
+type testTimestamps struct {
+	CreatedAt time.Time `psql:"created_at,readonly"`
+}
+
+type testUser struct {
+	ID       int64        `psql:"id,pk"`
+	Email    string       `psql:"email"`
+	Nickname string       `psql:"nickname,omitempty"`
+	Settings testSettings `psql:"settings,jsonb"`
+	Internal string       `psql:"-"`
+	testTimestamps
+}
+
+func TestTableDef(t *testing.T) {
+	users, err := DefineTable("users", testUser{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantNames := []string{"id", "email", "nickname", "settings", "created_at"}
+	if names := users.ColumnNames(); !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("expected %v, got %v", wantNames, names)
+	}
+
+	query := Select(
+		users.Columns()...,
+	).Where(
+		Eq(users.Column("Email"), users.Param("Email")),
+		GreaterThan(users.Column("CreatedAt"), users.Param("CreatedAt")),
+	)
+
+	st, err := query.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `SELECT "id", "email", "nickname", "settings", "created_at" FROM "users" WHERE ("email" = $1::text) AND ("created_at" > $2::timestamptz)`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	ts := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	bindings, err := st.BindStruct(struct {
+		Email string `psql:"email"`
+		testTimestamps
+	}{"joe@example.com", testTimestamps{ts}})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if want := []interface{}{"joe@example.com", ts}; !reflect.DeepEqual(bindings, want) {
+		t.Errorf("expected %v, got %v", want, bindings)
+	}
+
+	if _, err := Select(users.Column("Missing")).Build(); err == nil {
+		t.Error("expected an error for an unknown field, got nil")
+	}
+}
+
+type testMood string
+
+type testEvent struct {
+	ID      [16]byte `psql:"id,type=uuid"`
+	Mood    testMood `psql:"mood,type=public.mood"`
+	Tags    []string `psql:"tags,type=varchar[]"`
+	Payload [16]byte `psql:"payload"`
+	Price   string   `psql:"price,type=numeric(10,2),omitempty"`
+	At      string   `psql:"at,type=timestamp(3) with time zone"`
+	Bad     string   `psql:"bad,type=not a type"`
+}
+
+func TestTableDefParamTypes(t *testing.T) {
+	events, err := DefineTable("events", testEvent{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := Select(events.Column("ID")).Where(
+		Eq(events.Column("ID"), events.Param("ID")),
+		Eq(events.Column("Mood"), events.Param("Mood")),
+		Eq(events.Column("Tags"), events.Param("Tags")),
+		Eq(events.Column("Payload"), events.Param("Payload")),
+		Eq(events.Column("Price"), events.Param("Price")),
+		Eq(events.Column("At"), events.Param("At")),
+	)
+
+	st, err := query.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `SELECT "id" FROM "events" WHERE ("id" = $1::uuid) AND ("mood" = $2::"public"."mood") AND ("tags" = $3::varchar[]) AND ("payload" = $4) AND ("price" = $5::numeric(10,2)) AND ("at" = $6::timestamptz(3))`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	_, err = Select(events.Column("ID")).Where(Eq(events.Column("ID"), events.Param("Missing"))).Build()
+	if msg := "psql: WHERE[0].right: psql.testEvent has no field Missing tagged with a column name"; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+
+	_, err = Select(events.Column("ID")).Where(Eq(events.Column("Bad"), events.Param("Bad"))).Build()
+	if msg := `psql: WHERE[0].right: field Bad of psql.testEvent: invalid type "not a type"`; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
+
+func TestTableDefAssignments(t *testing.T) {
+	users, err := DefineTable("users", &testUser{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user := &testUser{ID: 42, Email: "joe@example.com", Settings: testSettings{"dark"}}
+
+	insert, err := users.Insert(user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	st, err := insert.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `INSERT INTO "users" ("id", "email", "settings") VALUES ($1::bigint, $2::text, $3::jsonb)`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	args := []interface{}{int64(42), "joe@example.com", []byte(`{"theme":"dark"}`)}
+	if !reflect.DeepEqual(st.Args, args) {
+		t.Errorf("expected %v, got %v", args, st.Args)
+	}
+
+	user.Nickname = "Joe"
+	update, err := users.Update(user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	st, err = update.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql = `UPDATE "users" SET "email" = $1::text, "nickname" = $2::text, "settings" = $3::jsonb WHERE ("id" = $4::bigint)`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	args = []interface{}{"joe@example.com", "Joe", []byte(`{"theme":"dark"}`), int64(42)}
+	if !reflect.DeepEqual(st.Args, args) {
+		t.Errorf("expected %v, got %v", args, st.Args)
+	}
+
+	if _, err := users.Insert(struct{}{}); err == nil {
+		t.Error("expected an error for a struct of the wrong type, got nil")
+	}
+}
+
+type testProduct struct {
+	ID    int64    `psql:"id,pk,type=integer"`
+	Price string   `psql:"price,type=numeric(10,2)"`
+	Tags  []string `psql:"tags,type=varchar(32)[]"`
+	Extra struct{} `psql:"extra"`
+}
+
+func TestTableDefTypedAssignments(t *testing.T) {
+	products, err := DefineTable("products", testProduct{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	product := testProduct{ID: 7, Price: "9.99", Tags: []string{"new"}}
+
+	insert, err := products.Insert(product)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	st, err := insert.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `INSERT INTO "products" ("id", "price", "tags", "extra") VALUES ($1::integer, $2::numeric(10,2), $3::varchar(32)[], $4)`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	args := []interface{}{int64(7), "9.99", pq.Array([]string{"new"}), struct{}{}}
+	if !reflect.DeepEqual(st.Args, args) {
+		t.Errorf("expected %v, got %v", args, st.Args)
+	}
+
+	update, err := products.Update(product)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	st, err = update.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql = `UPDATE "products" SET "price" = $1::numeric(10,2), "tags" = $2::varchar(32)[], "extra" = $3 WHERE ("id" = $4::integer)`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	events, err := DefineTable("events", testEvent{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = events.Insert(testEvent{})
+	if msg := `psql: field Bad of psql.testEvent: invalid type "not a type"`; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+}
+
+func TestDefineTableErrors(t *testing.T) {
+	cases := []interface{}{
+		42,
+		struct{ Name string }{},
+		struct {
+			A string `psql:"name"`
+			B string `psql:"name"`
+		}{},
+	}
+
+	for i, model := range cases {
+		if _, err := DefineTable("t", model); err == nil {
+			t.Errorf("test case %d: expected an error, got nil", i+1)
+		}
+	}
+}
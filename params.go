@@ -0,0 +1,225 @@
+package psql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func newParams() *Params {
+	return &Params{
+		values: make(map[int]interface{}),
+		names:  make(map[string]int),
+		free:   make(map[int]ParamInfo),
+	}
+}
+
+// Params keeps track of the parameters used by a query while it is being
+// converted to SQL, assigning each one a positional marker such as $1.
+type Params struct {
+	counter int
+	values  map[int]interface{}
+	names   map[string]int
+	free    map[int]ParamInfo
+
+	path []string
+	errs BuildErrors
+
+	// qualify is true while converting a query that lists more than one
+	// relation with From or Join, whose columns must be qualified with the
+	// name of their table to avoid ambiguity.
+	qualify bool
+}
+
+// ParamInfo describes a free parameter, whose value must be supplied when
+// the query is executed.
+type ParamInfo struct {
+	// Position is the parameter's position in the query, starting from 1.
+	Position int
+
+	// Name is the name of the parameter, or the empty string if the
+	// parameter is positional.
+	Name string
+
+	// Type is the type the parameter is cast to. The Type of a parameter
+	// allocated with Params.New is unknown, and prints as "unknown".
+	Type DataType
+}
+
+func (i ParamInfo) String() string {
+	if i.Name != "" {
+		return fmt.Sprintf("$%d (%q %s)", i.Position, i.Name, i.Type)
+	}
+	return fmt.Sprintf("$%d (%s)", i.Position, i.Type)
+}
+
+func (p *Params) Add(value interface{}) string {
+	marker := p.next()
+	p.values[p.counter] = value
+	return marker
+}
+
+// New returns the marker for a new positional free parameter whose type
+// is unknown.
+func (p *Params) New() string {
+	return p.newFree(unknownType)
+}
+
+func (p *Params) newFree(t DataType) string {
+	marker := p.next()
+	p.free[p.counter] = ParamInfo{Position: p.counter, Type: t}
+	return marker
+}
+
+// Named returns the marker for the free parameter with the given name,
+// allocating a new one the first time the name is used.
+func (p *Params) Named(name string, t DataType) string {
+	if pos, ok := p.names[name]; ok {
+		if prev := p.free[pos].Type; prev != t {
+			p.Errorf("named parameter %q is used with types %s and %s", name, prev, t)
+		}
+		return fmt.Sprintf("$%d", pos)
+	}
+
+	marker := p.next()
+	p.names[name] = p.counter
+	p.free[p.counter] = ParamInfo{Position: p.counter, Name: name, Type: t}
+	return marker
+}
+
+func (p *Params) next() string {
+	p.counter++
+	return fmt.Sprintf("$%d", p.counter)
+}
+
+// FreeParams returns a description of every free parameter, both positional
+// and named, in the order in which they appear in the query.
+func (p *Params) FreeParams() []ParamInfo {
+	var infos []ParamInfo
+	for i := 1; i <= p.counter; i++ {
+		if info, ok := p.free[i]; ok {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// Values returns the values of all parameters in order, using inputs to
+// fill in positional free parameters. Named parameters are not filled in.
+func (p *Params) Values(inputs []interface{}) []interface{} {
+	return p.statement("").Bindings(inputs...)
+}
+
+func (p *Params) Reset() {
+	if p.counter > 0 {
+		p.counter = 0
+		p.values = make(map[int]interface{})
+		p.names = make(map[string]int)
+		p.free = make(map[int]ParamInfo)
+	}
+	p.path = nil
+	p.errs = nil
+}
+
+// Errorf records a problem with the node of the query currently being
+// converted to SQL. The conversion carries on regardless, but the error
+// is returned by the query's Build method.
+func (p *Params) Errorf(format string, args ...interface{}) {
+	if p == nil {
+		return
+	}
+
+	p.errs = append(p.errs, &BuildError{
+		Path: strings.Join(p.path, "."),
+		Err:  fmt.Errorf(format, args...),
+	})
+}
+
+// Expr converts the Expression e, a child of the node currently being
+// converted to SQL, and records label as part of the path to any errors
+// found within it.
+func (p *Params) Expr(label string, e Expression) string {
+	p.enter(label)
+	defer p.leave()
+	return e.ToSQLExpr(p)
+}
+
+// Boolean is like Expr, but for a child BooleanExpression.
+func (p *Params) Boolean(label string, e BooleanExpression) string {
+	p.enter(label)
+	defer p.leave()
+	return e.ToSQLBoolean(p)
+}
+
+func (p *Params) enter(label string) {
+	if p != nil {
+		p.path = append(p.path, label)
+	}
+}
+
+func (p *Params) leave() {
+	if p != nil {
+		p.path = p.path[:len(p.path)-1]
+	}
+}
+
+// scope sets whether columns must be qualified while converting a query
+// with n relations in scope, and returns a function that restores the
+// setting of the enclosing query.
+func (p *Params) scope(n int) func() {
+	if p == nil {
+		return func() {}
+	}
+	prev := p.qualify
+	p.qualify = n > 1
+	return func() { p.qualify = prev }
+}
+
+// check records an error if e is not a valid value of its type.
+func (p *Params) check(e enum) {
+	if !e.valid() {
+		p.Errorf("unknown %s", unknownEnum(e))
+	}
+}
+
+// checkType records an error if t cannot be used to cast a value.
+func (p *Params) checkType(t DataType) {
+	if err := t.validate(); err != nil {
+		p.Errorf("%v", err)
+	}
+}
+
+// err returns the errors recorded so far, or nil if there were none.
+func (p *Params) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+// statement returns a Statement with the given SQL and a snapshot of the
+// parameters collected so far.
+func (p *Params) statement(sql string) Statement {
+	st := Statement{
+		SQL:        sql,
+		Args:       make([]interface{}, p.counter),
+		FreeParams: p.FreeParams(),
+	}
+
+	for pos, val := range p.values {
+		st.Args[pos-1] = val
+	}
+
+	return st
+}
+
+func quoteNames(names []string) string {
+	sort.Strings(names)
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,167 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Relation is the interface representing anything that can appear in the
+// FROM clause of a query, such as a table.
+//
+// ToSQLRelation converts the relation into a snippet of SQL that may be
+// safely embedded in a FROM clause.
+type Relation interface {
+	ToSQLRelation(*Params) string
+}
+
+// Table returns a Relation representing the database table with the given
+// name. A name of the form "schema.table" refers to a table in a specific
+// schema, and each part is quoted separately. Use SchemaTable for tables
+// whose names contain dots.
+func Table(name string) table {
+	return parseTable(name)
+}
+
+// SchemaTable returns a Relation representing the table with the given
+// name in the given schema. Unlike Table, it does not split name on dots,
+// so it can refer to tables whose names contain them. If schema is empty,
+// the table is not schema-qualified.
+func SchemaTable(schema, name string) table {
+	return table{schema, name}
+}
+
+type table struct {
+	schema, name string
+}
+
+func parseTable(name string) table {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return table{name[:i], name[i+1:]}
+	}
+	return table{name: name}
+}
+
+// Column returns an Expression representing the column col of the table t.
+// Unlike TableColumn, it can be used for tables whose names contain dots.
+func (t table) Column(col string) tableColumn {
+	return tableColumn{table: t, column: col}
+}
+
+// AllColumns returns an Expression representing all columns of the table
+// t. Unlike the AllColumns function, it can be used for tables whose names
+// contain dots.
+func (t table) AllColumns() allColumns {
+	return allColumns{t}
+}
+
+func (t table) ToSQLRelation(*Params) string {
+	return t.String()
+}
+
+// String returns the quoted, and possibly schema-qualified, name of the table.
+func (t table) String() string {
+	if t.schema == "" {
+		return pq.QuoteIdentifier(t.name)
+	}
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(t.schema), pq.QuoteIdentifier(t.name))
+}
+
+// A derivedTable is a Relation representing the result of a subquery.
+type derivedTable struct {
+	query   SelectQuery
+	alias   string
+	lateral bool
+}
+
+// Lateral returns a copy of the derivedTable d that is preceded by the
+// LATERAL key word, which allows the subquery to refer to columns of the
+// relations that come before it in the FROM clause.
+//
+// The subquery's FROM clause is inferred from the tables its expressions
+// refer to, so its references to the outer query must use columns returned
+// by Outer, or else the subquery must list its own relations with From.
+func (d derivedTable) Lateral() derivedTable {
+	d.lateral = true
+	return d
+}
+
+// Column returns an Expression representing the column with the given name
+// in the result of the subquery. Since such columns don't belong to a table,
+// they are not used to infer the FROM clause of the outer query.
+func (d derivedTable) Column(name string) aliasColumn {
+	return aliasColumn{d.alias, name}
+}
+
+func (d derivedTable) ToSQLRelation(p *Params) string {
+	p.enter("subquery")
+	query := d.query.toSQL(p)
+	p.leave()
+
+	sql := fmt.Sprintf("(%s) AS %s", query, pq.QuoteIdentifier(d.alias))
+	if d.lateral {
+		sql = "LATERAL " + sql
+	}
+	return sql
+}
+
+// aliasColumn is an Expression representing a column of a relation that
+// is referred to by its alias, such as a subquery.
+type aliasColumn struct {
+	alias, column string
+}
+
+func (ac aliasColumn) ToSQLExpr(*Params) string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(ac.alias), pq.QuoteIdentifier(ac.column))
+}
+
+func (aliasColumn) Relations() []string {
+	return nil
+}
+
+type join struct {
+	joinType joinType
+	rel      Relation
+	on       BooleanExpression
+}
+
+func (j join) toSQL(p *Params) string {
+	p.check(j.joinType)
+	rel := j.rel.ToSQLRelation(p)
+
+	on := "true"
+	if j.on != nil {
+		on = p.Boolean("on", j.on)
+	}
+
+	return fmt.Sprintf("%s %s ON %s", j.joinType, rel, on)
+}
+
+type joinType int
+
+const (
+	innerJoin joinType = iota
+	leftJoin
+	rightJoin
+	fullJoin
+)
+
+func (j joinType) String() string {
+	switch j {
+	case innerJoin:
+		return "JOIN"
+	case leftJoin:
+		return "LEFT JOIN"
+	case rightJoin:
+		return "RIGHT JOIN"
+	case fullJoin:
+		return "FULL JOIN"
+	default:
+		return unknownEnum(j)
+	}
+}
+
+func (j joinType) valid() bool {
+	return j >= innerJoin && j <= fullJoin
+}
@@ -0,0 +1,121 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDerivedTables(t *testing.T) {
+	totals := Select(
+		TableColumn("orders", "user_id"),
+		As(Sum(TableColumn("orders", "total")), "sum"),
+	).Where(
+		GreaterThan(TableColumn("orders", "total"), IntParam()),
+	).GroupBy(
+		TableColumn("orders", "user_id"),
+	).As("totals")
+
+	recent := Select(
+		TableColumn("orders", "total"),
+	).Where(
+		Eq(TableColumn("orders", "user_id").Qualified(), TableColumn("users", "id").Outer()),
+		NotEq(TableColumn("orders", "status"), StringLiteral("cancelled")),
+	).OrderBy(
+		Descending(TableColumn("orders", "created_at")),
+	).Limit(3).As("recent").Lateral()
+
+	cases := []struct {
+		query  SelectQuery
+		inputs []interface{}
+
+		sql      string
+		bindings []interface{}
+	}{
+		{
+			Select(
+				totals.Column("user_id"),
+				totals.Column("sum"),
+			).From(
+				totals,
+			).Where(
+				GreaterThan(totals.Column("sum"), IntParam()),
+			),
+			[]interface{}{10, 1000},
+
+			`SELECT "totals"."user_id", "totals"."sum" FROM (SELECT "user_id", SUM("total") AS "sum" FROM "orders" WHERE ("total" > $1::integer) GROUP BY "user_id") AS "totals" WHERE ("totals"."sum" > $2::integer)`,
+			[]interface{}{10, 1000},
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+				StringLiteral("Hello"),
+				recent.Column("total"),
+			).LeftJoin(
+				recent, nil,
+			).Where(
+				NotEq(TableColumn("users", "name"), StringParam()),
+			),
+			[]interface{}{"Joe"},
+
+			`SELECT "users"."name", $1::text, "recent"."total" FROM "users" LEFT JOIN LATERAL (SELECT "total" FROM "orders" WHERE ("orders"."user_id" = "users"."id") AND ("status" <> $2::text) ORDER BY "created_at" DESC LIMIT 3) AS "recent" ON true WHERE ("users"."name" <> $3::text)`,
+			[]interface{}{"Hello", "cancelled", "Joe"},
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+				TableColumn("orders", "total"),
+			).Join(
+				Table("orders"),
+				Eq(TableColumn("orders", "user_id").Qualified(), TableColumn("users", "id").Qualified()),
+			),
+			nil,
+
+			`SELECT "users"."name", "orders"."total" FROM "users" JOIN "orders" ON ("orders"."user_id" = "users"."id")`,
+			nil,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).OrderBy(
+				Ascending(TableColumn("users", "name")),
+			).Limit(10).Offset(20),
+			nil,
+
+			`SELECT "name" FROM "users" ORDER BY "name" ASC LIMIT 10 OFFSET 20`,
+			nil,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+
+		bindings := st.Bindings(tc.inputs...)
+		if !reflect.DeepEqual(bindings, tc.bindings) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.bindings, bindings)
+		}
+	}
+}
+
+func TestDerivedTableErrors(t *testing.T) {
+	cases := []SelectQuery{
+		Select(Now()).Join(Table("users"), nil),
+		Select(TableColumn("users", "name")).Limit(-1),
+		Select(TableColumn("users", "name")).From(
+			Select(comparison{IntLiteral(1), IntLiteral(2), comparisonType(42)}).As("sub"),
+		),
+	}
+
+	for i, query := range cases {
+		if _, err := query.Build(); err == nil {
+			t.Errorf("test case %d: expected an error, got nil", i+1)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// A Queryer executes queries that return rows. It is satisfied by *sql.DB,
+// *sql.Tx and *sql.Conn.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SelectAll executes the query s using q, replacing its free parameters
+// with inputs as Query does, and scans the resulting rows into dest as
+// ScanAll does.
+func SelectAll(ctx context.Context, q Queryer, s SelectQuery, dest interface{}, inputs ...interface{}) error {
+	rows, err := Query(ctx, q, s, inputs...)
+	if err != nil {
+		return err
+	}
+	return ScanAll(rows, dest)
+}
+
+// SelectOne executes the query s using q, replacing its free parameters
+// with inputs as Query does, and scans the first resulting row into dest as
+// ScanOne does.
+func SelectOne(ctx context.Context, q Queryer, s SelectQuery, dest interface{}, inputs ...interface{}) error {
+	rows, err := Query(ctx, q, s, inputs...)
+	if err != nil {
+		return err
+	}
+	return ScanOne(rows, dest)
+}
+
+// ScanAll scans every row in rows into dest, which must be a pointer to
+// a slice of structs or of pointers to structs, and then closes rows. Any
+// elements already in the slice are discarded.
+//
+// Each column in the result is stored in the struct field whose tag
+// matches the column's name or alias, including the fields of embedded
+// structs. As with Go's own field selectors, a field shadows deeper fields
+// with the same tag, and two such fields at the same depth are an error.
+// Nullable columns may be scanned into pointer fields or into
+// types such as sql.NullString; fields tagged as jsonb are decoded from
+// JSON. It is an error for a column to have no corresponding field.
+func ScanAll(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.IsNil() || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("psql: expected a pointer to a slice, got %T", dest)
+	}
+	slice = slice.Elem()
+
+	elem := slice.Type().Elem()
+	byPtr := elem.Kind() == reflect.Ptr
+	if byPtr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("psql: expected a slice of structs, got %s", slice.Type())
+	}
+
+	plan, err := newScanPlan(rows, elem)
+	if err != nil {
+		return err
+	}
+
+	slice.Set(slice.Slice(0, 0))
+
+	for rows.Next() {
+		rv := reflect.New(elem)
+		if err := plan.scan(rows, rv.Elem()); err != nil {
+			return err
+		}
+
+		if byPtr {
+			slice.Set(reflect.Append(slice, rv))
+		} else {
+			slice.Set(reflect.Append(slice, rv.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// ScanOne scans the first row in rows into dest, which must be a pointer to
+// a struct, and then closes rows. Columns are matched to fields as they are
+// by ScanAll. If there are no rows, ScanOne returns sql.ErrNoRows.
+func ScanOne(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("psql: expected a pointer to a struct, got %T", dest)
+	}
+	rv = rv.Elem()
+
+	plan, err := newScanPlan(rows, rv.Type())
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := plan.scan(rows, rv); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+// A scanPlan holds the struct field that each column in a result is
+// scanned into.
+type scanPlan struct {
+	fields []structField
+}
+
+func newScanPlan(rows *sql.Rows, t reflect.Type) (scanPlan, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return scanPlan{}, err
+	}
+
+	fields, err := dominantFields(t)
+	if err != nil {
+		return scanPlan{}, err
+	}
+
+	byName := make(map[string]structField)
+	for _, f := range fields {
+		byName[f.name] = f
+	}
+
+	plan := scanPlan{fields: make([]structField, len(columns))}
+	seen := make(map[string]bool)
+	var unmapped []string
+
+	for i, col := range columns {
+		if seen[col] {
+			return scanPlan{}, fmt.Errorf("psql: column %q appears more than once in the result", col)
+		}
+		seen[col] = true
+
+		f, ok := byName[col]
+		if !ok {
+			unmapped = append(unmapped, col)
+			continue
+		}
+		plan.fields[i] = f
+	}
+
+	if len(unmapped) > 0 {
+		return scanPlan{}, fmt.Errorf("psql: %s has no fields for columns %s", t, quoteNames(unmapped))
+	}
+
+	return plan, nil
+}
+
+// scan scans the current row into the struct rv, allocating any nil
+// embedded struct pointers along the way.
+func (p scanPlan) scan(rows *sql.Rows, rv reflect.Value) error {
+	dest := make([]interface{}, len(p.fields))
+
+	for i, f := range p.fields {
+		fv, err := allocFieldByIndex(rv, f.index)
+		if err != nil {
+			return err
+		}
+
+		ptr := fv.Addr().Interface()
+		if f.hasOption("jsonb") {
+			dest[i] = jsonScanner{ptr}
+		} else {
+			dest[i] = ptr
+		}
+	}
+
+	return rows.Scan(dest...)
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex, but allocates any
+// nil embedded pointers it encounters. It returns an error if one of them
+// cannot be set because its type is unexported.
+func allocFieldByIndex(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("psql: cannot allocate embedded pointer to unexported type %s", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+// jsonScanner decodes a JSON column into the value pointed to by dest.
+// A NULL column leaves the value untouched.
+type jsonScanner struct {
+	dest interface{}
+}
+
+func (j jsonScanner) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, j.dest)
+	case string:
+		return json.Unmarshal([]byte(data), j.dest)
+	default:
+		return fmt.Errorf("psql: cannot decode %T as JSON", src)
+	}
+}
@@ -0,0 +1,208 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ScanAudit struct {
+	UpdatedAt time.Time `psql:"updated_at"`
+}
+
+type scanUser struct {
+	ID       int64          `psql:"id"`
+	Name     string         `psql:"name"`
+	Nickname *string        `psql:"nickname"`
+	Bio      sql.NullString `psql:"bio"`
+	Settings testSettings   `psql:"settings,jsonb"`
+	*ScanAudit
+}
+
+func TestSelectAll(t *testing.T) {
+	ts := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	nickname := "Joey"
+
+	var gotSQL string
+	var gotArgs []driver.Value
+
+	db, _ := openFakeDB(func(query string, args []driver.Value) (*fakeRows, error) {
+		gotSQL, gotArgs = query, args
+		return &fakeRows{
+			columns: []string{"id", "name", "nickname", "bio", "settings", "updated_at"},
+			values: [][]driver.Value{
+				{int64(1), "Joe", "Joey", nil, []byte(`{"theme":"dark"}`), ts},
+				{int64(2), "Jane", nil, "Hi", nil, ts},
+			},
+		}, nil
+	})
+	defer db.Close()
+
+	query := Select(
+		TableColumn("users", "id"),
+		TableColumn("users", "name"),
+		TableColumn("users", "nickname"),
+		TableColumn("users", "bio"),
+		TableColumn("users", "settings"),
+		TableColumn("users", "updated_at"),
+	).Where(
+		NotEq(TableColumn("users", "name"), StringParam()),
+	)
+
+	var users []scanUser
+	if err := SelectAll(context.Background(), db, query, &users, "Jim"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sql := query.ToSQL(); gotSQL != sql {
+		t.Errorf("expected %q, got %q", sql, gotSQL)
+	}
+
+	if args := []driver.Value{"Jim"}; !reflect.DeepEqual(gotArgs, args) {
+		t.Errorf("expected %v, got %v", args, gotArgs)
+	}
+
+	expected := []scanUser{
+		{
+			ID:        1,
+			Name:      "Joe",
+			Nickname:  &nickname,
+			Settings:  testSettings{"dark"},
+			ScanAudit: &ScanAudit{ts},
+		},
+		{
+			ID:        2,
+			Name:      "Jane",
+			Bio:       sql.NullString{String: "Hi", Valid: true},
+			ScanAudit: &ScanAudit{ts},
+		},
+	}
+
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %+v, got %+v", expected, users)
+	}
+}
+
+func TestSelectOne(t *testing.T) {
+	var rows [][]driver.Value
+
+	db, _ := openFakeDB(func(string, []driver.Value) (*fakeRows, error) {
+		return &fakeRows{
+			columns: []string{"id", "display_name"},
+			values:  rows,
+		}, nil
+	})
+	defer db.Close()
+
+	query := Select(
+		TableColumn("users", "id"),
+		As(TableColumn("users", "name"), "display_name"),
+	)
+
+	var user struct {
+		ID   int64  `psql:"id"`
+		Name string `psql:"display_name"`
+	}
+
+	if err := SelectOne(context.Background(), db, query, &user); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	rows = [][]driver.Value{{int64(1), "Joe"}, {int64(2), "Jane"}}
+	if err := SelectOne(context.Background(), db, query, &user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.ID != 1 || user.Name != "Joe" {
+		t.Errorf("expected {1 Joe}, got %+v", user)
+	}
+}
+
+type ScanName struct {
+	Name string `psql:"name"`
+}
+
+type ScanLabel struct {
+	Label string `psql:"name"`
+}
+
+type scanAmbiguous struct {
+	ID int64 `psql:"id"`
+	ScanName
+	ScanLabel
+}
+
+func TestScanShadowedFields(t *testing.T) {
+	db, _ := openFakeDB(func(string, []driver.Value) (*fakeRows, error) {
+		return &fakeRows{
+			columns: []string{"id", "name"},
+			values:  [][]driver.Value{{int64(1), "Joe"}},
+		}, nil
+	})
+	defer db.Close()
+
+	query := Select(TableColumn("users", "id"), TableColumn("users", "name"))
+
+	// As in Go, the shallower field shadows the embedded one.
+	var user struct {
+		ID   int64  `psql:"id"`
+		Name string `psql:"name"`
+		ScanName
+	}
+
+	if err := SelectOne(context.Background(), db, query, &user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.Name != "Joe" || user.ScanName.Name != "" {
+		t.Errorf("expected only the outer Name to be set, got %+v", user)
+	}
+
+	var ambiguous scanAmbiguous
+
+	err := SelectOne(context.Background(), db, query, &ambiguous)
+	if msg := `psql: fields Name and Label of psql.scanAmbiguous are both tagged "name"`; err == nil || err.Error() != msg {
+		t.Errorf("expected %q, got %v", msg, err)
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	db, _ := openFakeDB(func(query string, _ []driver.Value) (*fakeRows, error) {
+		columns := []string{"id", "email", "age"}
+		if query == `SELECT "id", "id" FROM "users"` {
+			columns = []string{"id", "id"}
+		}
+		return &fakeRows{columns: columns}, nil
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	query := Select(TableColumn("users", "id"))
+
+	var users []scanUser
+	err := SelectAll(ctx, db, query, &users)
+	if msg := `psql: psql.scanUser has no fields for columns "age", "email"`; err == nil || err.Error() != msg {
+		t.Errorf("expected %q, got %v", msg, err)
+	}
+
+	err = SelectAll(ctx, db, Select(TableColumn("users", "id"), TableColumn("users", "id")), &users)
+	if msg := `psql: column "id" appears more than once in the result`; err == nil || err.Error() != msg {
+		t.Errorf("expected %q, got %v", msg, err)
+	}
+
+	if err := SelectAll(ctx, db, query, users); err == nil {
+		t.Error("expected an error for a non-pointer destination, got nil")
+	}
+
+	var ids []int64
+	if err := SelectAll(ctx, db, query, &ids); err == nil {
+		t.Error("expected an error for a slice of non-structs, got nil")
+	}
+
+	if err := SelectAll(ctx, db, query.Where(Eq(TableColumn("users", "id"), IntParam())), &users); err == nil {
+		t.Error("expected an error for a missing binding, got nil")
+	}
+}
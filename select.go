@@ -12,8 +12,7 @@ import (
 // "SELECT" and "FROM".
 func Select(exprs ...Expression) SelectQuery {
 	return SelectQuery{
-		sel:    selectClause{exprs},
-		params: newParams(),
+		sel: selectClause{exprs},
 	}
 }
 
@@ -24,7 +23,84 @@ type SelectQuery struct {
 	where   whereClause
 	groupBy groupByClause
 
-	params *Params
+	// If explicitFrom is true, the FROM clause only contains the relations
+	// in fromList, rather than those inferred from the query's expressions.
+	fromList     []Relation
+	explicitFrom bool
+
+	joins []join
+
+	limit, offset limitClause
+}
+
+// From returns a copy of the SelectQuery s whose FROM clause contains the
+// given relations, in order. By default, the FROM clause is inferred from
+// the tables referenced by the query's expressions; calling From turns off
+// this inference, so every relation the query needs must be listed: the
+// given relations replace the inferred ones rather than being added to
+// them. This method overwrites any relations passed to a previous call to
+// From. If the query has more than one relation, its columns are qualified
+// with the names of their tables.
+func (s SelectQuery) From(rels ...Relation) SelectQuery {
+	s.fromList = rels
+	s.explicitFrom = true
+	return s
+}
+
+// Join returns a copy of the SelectQuery s with an additional INNER JOIN
+// between the FROM list and rel, matching rows on the condition on. If on
+// is nil, the condition is the constant TRUE, as is usual for LATERAL joins.
+//
+// Tables joined in this way are excluded from the FROM list inferred from
+// the query's expressions, and the query's columns are qualified with the
+// names of their tables.
+func (s SelectQuery) Join(rel Relation, on BooleanExpression) SelectQuery {
+	return s.join(innerJoin, rel, on)
+}
+
+// LeftJoin is like Join, but performs a LEFT OUTER JOIN.
+func (s SelectQuery) LeftJoin(rel Relation, on BooleanExpression) SelectQuery {
+	return s.join(leftJoin, rel, on)
+}
+
+// RightJoin is like Join, but performs a RIGHT OUTER JOIN.
+func (s SelectQuery) RightJoin(rel Relation, on BooleanExpression) SelectQuery {
+	return s.join(rightJoin, rel, on)
+}
+
+// FullJoin is like Join, but performs a FULL OUTER JOIN.
+func (s SelectQuery) FullJoin(rel Relation, on BooleanExpression) SelectQuery {
+	return s.join(fullJoin, rel, on)
+}
+
+func (s SelectQuery) join(jt joinType, rel Relation, on BooleanExpression) SelectQuery {
+	joins := make([]join, len(s.joins), len(s.joins)+1)
+	copy(joins, s.joins)
+	s.joins = append(joins, join{jt, rel, on})
+	return s
+}
+
+// Limit returns a copy of the SelectQuery s with an additional LIMIT clause,
+// so that at most n rows are returned. If a LIMIT clause was already present,
+// this method will overwrite it.
+func (s SelectQuery) Limit(n int) SelectQuery {
+	s.limit = limitClause{"LIMIT", n, true}
+	return s
+}
+
+// Offset returns a copy of the SelectQuery s with an additional OFFSET
+// clause, so that the first n rows are skipped. If an OFFSET clause was
+// already present, this method will overwrite it.
+func (s SelectQuery) Offset(n int) SelectQuery {
+	s.offset = limitClause{"OFFSET", n, true}
+	return s
+}
+
+// As returns a Relation representing the result of the SelectQuery s under
+// the given alias, for use in the FROM clause of another query. The columns
+// of the result can be referred to with the Column method of the Relation.
+func (s SelectQuery) As(alias string) derivedTable {
+	return derivedTable{query: s, alias: alias}
 }
 
 // OrderBy returns a copy of the SelectQuery s with an additional ORDER BY
@@ -54,20 +130,54 @@ func (s SelectQuery) Where(exprs ...BooleanExpression) SelectQuery {
 // ToSQL returns a string containing the full SQL query version of the
 // SelectQuery. If the query is empty, an empty string is returned.
 func (s SelectQuery) ToSQL() string {
+	return s.Compile().SQL
+}
+
+// Compile converts the SelectQuery into a Statement holding both its SQL
+// representation and its arguments. Since the SelectQuery is not modified,
+// the same query can be compiled concurrently from multiple goroutines.
+//
+// Compile does not report problems with the query, such as an unknown
+// operator; the resulting SQL is then invalid. Use Build instead when the
+// query is constructed from user input.
+func (s SelectQuery) Compile() Statement {
+	p := newParams()
+	return p.statement(s.toSQL(p))
+}
+
+// Build is like Compile, but returns a BuildErrors describing every
+// problem found in the query, if any.
+func (s SelectQuery) Build() (Statement, error) {
+	p := newParams()
+	sql := s.toSQL(p)
+
+	if err := p.err(); err != nil {
+		return Statement{}, err
+	}
+
+	return p.statement(sql), nil
+}
+
+func (s SelectQuery) toSQL(p *Params) string {
 	var parts []string
 
-	// Since ToSQL() may be called multiple times, we must reset the params
-	// so that we always start with a blank slate.
-	s.params.Reset()
+	// Columns are only qualified automatically when the relations are given
+	// with From or Join. Queries whose FROM clause is inferred keep
+	// rendering their columns as written.
+	scope := 0
+	if s.explicitFrom || len(s.joins) > 0 {
+		scope = s.from().scope()
+	}
+	defer p.scope(scope)()
 
 	for _, clause := range s.clauses() {
-		if part := clause.ToSQLClause(s.params); part != "" {
+		if part := clause.ToSQLClause(p); part != "" {
 			parts = append(parts, part)
 		}
 	}
 
-	if len(parts) == 0 {
-		return ""
+	if len(parts) > 0 && len(s.sel.exprs) == 0 {
+		p.Errorf("the SELECT list is empty")
 	}
 
 	return strings.Join(parts, " ")
@@ -80,22 +190,35 @@ func (s SelectQuery) clauses() []Clause {
 		s.where,
 		s.groupBy,
 		s.orderBy,
+		s.limit,
+		s.offset,
 	}
 }
 
-func (s SelectQuery) from() Clause {
-	rels := make([]string, 0)
+func (s SelectQuery) from() fromClause {
+	if s.explicitFrom {
+		return fromClause{s.fromList, s.joins}
+	}
+
+	rels := make([]Relation, 0)
 	set := make(map[string]struct{})
 
+	// Tables that are joined explicitly must not also appear in the list.
+	for _, j := range s.joins {
+		if t, ok := j.rel.(table); ok {
+			set[t.String()] = struct{}{}
+		}
+	}
+
 	for _, rel := range s.relations() {
 		// Have we seen this relation before?
 		if _, ok := set[rel]; !ok {
 			set[rel] = struct{}{}
-			rels = append(rels, rel)
+			rels = append(rels, inferredRelation(rel))
 		}
 	}
 
-	return fromClause{rels}
+	return fromClause{rels, s.joins}
 }
 
 func (s SelectQuery) relations() []string {
@@ -104,6 +227,11 @@ func (s SelectQuery) relations() []string {
 	rels = append(rels, s.where.Relations()...)
 	rels = append(rels, s.groupBy.Relations()...)
 	rels = append(rels, s.orderBy.Relations()...)
+	for _, j := range s.joins {
+		if j.on != nil {
+			rels = append(rels, j.on.Relations()...)
+		}
+	}
 	return rels
 }
 
@@ -114,7 +242,51 @@ func (s SelectQuery) relations() []string {
 // user-supplied parameters in the SELECT query, in the same order as
 // they appear in the query.
 func (s SelectQuery) Bindings(inputs ...interface{}) []interface{} {
-	return s.params.Values(inputs)
+	return s.Compile().Bindings(inputs...)
+}
+
+// CheckedBindings is like Bindings, but returns an *ArgCountError if the
+// number of inputs differs from the number of positional free parameters
+// in the query, instead of silently ignoring the extra inputs or leaving
+// out the missing ones.
+func (s SelectQuery) CheckedBindings(inputs ...interface{}) ([]interface{}, error) {
+	return s.Compile().CheckedBindings(inputs...)
+}
+
+// StrictBindings is like CheckedBindings, but also returns an *ArgTypeError
+// if the Go type of any input is incompatible with the type of the free
+// parameter it replaces, such as a string supplied for an IntParam.
+func (s SelectQuery) StrictBindings(inputs ...interface{}) ([]interface{}, error) {
+	return s.Compile().StrictBindings(inputs...)
+}
+
+// FreeParams returns a description of each free parameter in the query,
+// in the order in which they appear.
+func (s SelectQuery) FreeParams() []ParamInfo {
+	return s.Compile().FreeParams
+}
+
+// BindNamed returns a slice of arguments that can be unpacked and passed
+// into the Query and QueryRow methods of the database/sql package, using
+// the values in args to replace the named parameters created with Param.
+//
+// An error is returned if args is missing a value for any of the named
+// parameters in the query, if it contains a name that does not appear in
+// the query, or if the query also contains positional free parameters.
+func (s SelectQuery) BindNamed(args map[string]interface{}) ([]interface{}, error) {
+	return s.Compile().BindNamed(args)
+}
+
+// BindStruct is like BindNamed, but takes the values of named parameters
+// from the fields of the struct v (or pointer to struct) instead of a map.
+// Each exported field tagged with `psql:"name"` provides the value of the
+// parameter with that name; fields of embedded structs are included too.
+//
+// As with BindNamed, an error is returned if a parameter has no field, or
+// if a tagged field does not correspond to any parameter in the query.
+// Fields tagged with `psql:"-"` and untagged fields are ignored.
+func (s SelectQuery) BindStruct(v interface{}) ([]interface{}, error) {
+	return s.Compile().BindStruct(v)
 }
 
 // Clause is the interface that represents the individual components of
@@ -137,7 +309,7 @@ func (s selectClause) ToSQLClause(p *Params) string {
 
 	args := make([]string, len(s.exprs))
 	for i, expr := range s.exprs {
-		args[i] = expr.ToSQLExpr(p)
+		args[i] = p.Expr(fmt.Sprintf("SELECT[%d]", i), expr)
 	}
 
 	return fmt.Sprintf("SELECT %s", strings.Join(args, ", "))
@@ -152,15 +324,47 @@ func (s selectClause) Relations() []string {
 }
 
 type fromClause struct {
-	rels []string
+	rels  []Relation
+	joins []join
+}
+
+// scope returns the number of relations that the clause brings into scope.
+func (f fromClause) scope() int {
+	return len(f.rels) + len(f.joins)
 }
 
-func (f fromClause) ToSQLClause(*Params) string {
+func (f fromClause) ToSQLClause(p *Params) string {
 	if len(f.rels) == 0 {
+		if len(f.joins) > 0 {
+			p.Errorf("cannot JOIN without any other relations in the FROM clause")
+		}
 		return ""
 	}
 
-	return fmt.Sprintf("FROM %s", strings.Join(f.rels, ", "))
+	parts := make([]string, len(f.rels))
+	for i, rel := range f.rels {
+		p.enter(fmt.Sprintf("FROM[%d]", i))
+		parts[i] = rel.ToSQLRelation(p)
+		p.leave()
+	}
+
+	sql := fmt.Sprintf("FROM %s", strings.Join(parts, ", "))
+
+	for i, j := range f.joins {
+		p.enter(fmt.Sprintf("JOIN[%d]", i))
+		sql = fmt.Sprintf("%s %s", sql, j.toSQL(p))
+		p.leave()
+	}
+
+	return sql
+}
+
+// inferredRelation is a Relation whose quoted name was returned by the
+// Relations method of one of the query's expressions.
+type inferredRelation string
+
+func (r inferredRelation) ToSQLRelation(*Params) string {
+	return string(r)
 }
 
 type whereClause struct {
@@ -174,7 +378,7 @@ func (w whereClause) ToSQLClause(p *Params) string {
 
 	conds := make([]string, len(w.exprs))
 	for i, expr := range w.exprs {
-		conds[i] = expr.ToSQLBoolean(p)
+		conds[i] = p.Boolean(fmt.Sprintf("WHERE[%d]", i), expr)
 	}
 
 	return fmt.Sprintf("WHERE %s", strings.Join(conds, " AND "))
@@ -209,7 +413,7 @@ func (g groupByClause) ToSQLClause(p *Params) string {
 
 	parts := make([]string, len(g.exprs))
 	for i, expr := range g.exprs {
-		parts[i] = expr.ToSQLExpr(p)
+		parts[i] = p.Expr(fmt.Sprintf("GROUP BY[%d]", i), expr)
 	}
 
 	return fmt.Sprintf("GROUP BY %s", strings.Join(parts, ", "))
@@ -234,7 +438,9 @@ func (o orderByClause) ToSQLClause(p *Params) string {
 
 	parts := make([]string, len(o.exprs))
 	for i, expr := range o.exprs {
+		p.enter(fmt.Sprintf("ORDER BY[%d]", i))
 		parts[i] = expr.ToSQLOrder(p)
+		p.leave()
 	}
 
 	return fmt.Sprintf("ORDER BY %s", strings.Join(parts, ", "))
@@ -248,16 +454,36 @@ func (o orderByClause) Relations() []string {
 	return rels
 }
 
+// limitClause represents either a LIMIT or an OFFSET clause, depending on
+// its key word.
+type limitClause struct {
+	keyword string
+	count   int
+	present bool
+}
+
+func (l limitClause) ToSQLClause(p *Params) string {
+	if !l.present {
+		return ""
+	}
+
+	if l.count < 0 {
+		p.Errorf("%s must not be negative, got %d", l.keyword, l.count)
+	}
+
+	return fmt.Sprintf("%s %d", l.keyword, l.count)
+}
+
 // Ascending returns a new OrderExpression specifying that the results
 // of the query must be ordered by the given Expression in ascending order.
 func Ascending(expr Expression) OrderExpression {
-	return OrderExpression{expr, asc}
+	return OrderExpression{expr: expr, direction: asc}
 }
 
 // Descending returns a new OrderExpression specifying that the results
 // of the query must be ordered by the given Expression in descending order.
 func Descending(expr Expression) OrderExpression {
-	return OrderExpression{expr, desc}
+	return OrderExpression{expr: expr, direction: desc}
 }
 
 // An OrderExpression is each individual component of a SELECT query's
@@ -266,10 +492,49 @@ func Descending(expr Expression) OrderExpression {
 type OrderExpression struct {
 	expr      Expression
 	direction orderDirection
+	nulls     nullsOrder
+	collation string
+}
+
+// NullsFirst returns a copy of the OrderExpression o specifying that
+// NULL values must be sorted before all non-NULL values.
+func (o OrderExpression) NullsFirst() OrderExpression {
+	o.nulls = nullsFirst
+	return o
+}
+
+// NullsLast returns a copy of the OrderExpression o specifying that
+// NULL values must be sorted after all non-NULL values.
+func (o OrderExpression) NullsLast() OrderExpression {
+	o.nulls = nullsLast
+	return o
+}
+
+// Collate returns a copy of the OrderExpression o that sorts values
+// using the collation with the given name, such as "en-US-x-icu".
+func (o OrderExpression) Collate(collation string) OrderExpression {
+	o.collation = collation
+	return o
 }
 
 func (o OrderExpression) ToSQLOrder(p *Params) string {
-	return fmt.Sprintf("%s %s", o.expr.ToSQLExpr(p), o.direction)
+	p.check(o.direction)
+	p.check(o.nulls)
+
+	sql := fmt.Sprintf("%s %s", o.sortKey(p), o.direction)
+	if o.nulls != defaultNulls {
+		sql = fmt.Sprintf("%s %s", sql, o.nulls)
+	}
+	return sql
+}
+
+// sortKey returns the SQL representation of the expression being sorted
+// on, including its COLLATE clause if a collation was specified.
+func (o OrderExpression) sortKey(p *Params) string {
+	if o.collation == "" {
+		return p.Expr("expr", o.expr)
+	}
+	return fmt.Sprintf("%s COLLATE %s", p.Expr("expr", o.expr), pq.QuoteIdentifier(o.collation))
 }
 
 func (o OrderExpression) Relations() []string {
@@ -290,10 +555,39 @@ func (o orderDirection) String() string {
 	case desc:
 		return "DESC"
 	default:
-		panic("unknown orderDirection")
+		return unknownEnum(o)
+	}
+}
+
+func (o orderDirection) valid() bool {
+	return o == asc || o == desc
+}
+
+type nullsOrder int
+
+const (
+	defaultNulls nullsOrder = iota
+	nullsFirst
+	nullsLast
+)
+
+func (n nullsOrder) String() string {
+	switch n {
+	case defaultNulls:
+		return ""
+	case nullsFirst:
+		return "NULLS FIRST"
+	case nullsLast:
+		return "NULLS LAST"
+	default:
+		return unknownEnum(n)
 	}
 }
 
+func (n nullsOrder) valid() bool {
+	return n >= defaultNulls && n <= nullsLast
+}
+
 // Expression is the interface that represents any SQL expression that
 // can be used in the SELECT list of an SQL query.
 //
@@ -309,90 +603,100 @@ type Expression interface {
 }
 
 // AllColumns returns an Expression representing all columns in the table.
-func AllColumns(table string) allColumns {
-	return allColumns{table}
+// As with Table, the name may be schema-qualified, such as "schema.table".
+func AllColumns(name string) allColumns {
+	return allColumns{parseTable(name)}
 }
 
 type allColumns struct {
-	table string
+	table table
 }
 
 func (ac allColumns) ToSQLExpr(*Params) string {
-	return fmt.Sprintf("%s.*", pq.QuoteIdentifier(ac.table))
+	return fmt.Sprintf("%s.*", ac.table)
 }
 
 func (ac allColumns) Relations() []string {
 	return []string{
-		pq.QuoteIdentifier(ac.table),
+		ac.table.String(),
 	}
 }
 
 // TableColumn returns an Expression representing the column col of the
-// database table with the given name.
-func TableColumn(table, col string) tableColumn {
-	return tableColumn{table, col}
+// database table with the given name. As with Table, the name may be
+// schema-qualified, such as "schema.table".
+func TableColumn(name, col string) tableColumn {
+	return tableColumn{table: parseTable(name), column: col}
 }
 
 type tableColumn struct {
-	table, column string
-}
+	table     table
+	column    string
+	qualified bool
+	outer     bool
+
+	// err, if not empty, is reported when the column is converted to SQL.
+	err string
+}
+
+// Qualified returns a copy of the column that is preceded by the name of
+// its table, such as "users"."id". Columns are qualified automatically in
+// queries that list more than one relation with From or Join, so this is
+// only needed in queries whose FROM clause is inferred. To refer to the
+// columns of an outer query, use Outer instead.
+func (tc tableColumn) Qualified() tableColumn {
+	tc.qualified = true
+	return tc
+}
+
+// Outer returns a copy of the column that refers to a relation of an outer
+// query, such as in a correlated subquery or a LATERAL one. The column is
+// qualified with the name of its table and, unlike other columns, is not
+// used to infer the FROM clause of the query it appears in, where its
+// table would otherwise shadow that of the outer query.
+func (tc tableColumn) Outer() tableColumn {
+	tc.qualified = true
+	tc.outer = true
+	return tc
+}
+
+func (tc tableColumn) ToSQLExpr(p *Params) string {
+	if tc.err != "" {
+		p.Errorf("%s", tc.err)
+	}
 
-func (tc tableColumn) ToSQLExpr(*Params) string {
+	if tc.qualified || (p != nil && p.qualify) {
+		return fmt.Sprintf("%s.%s", tc.table, pq.QuoteIdentifier(tc.column))
+	}
 	return pq.QuoteIdentifier(tc.column)
 }
 
 func (tc tableColumn) Relations() []string {
-	return []string{
-		pq.QuoteIdentifier(tc.table),
+	if tc.outer {
+		return nil
 	}
-}
-
-func newParams() *Params {
-	return &Params{
-		values: make(map[int]interface{}),
+	return []string{
+		tc.table.String(),
 	}
 }
 
-type Params struct {
-	counter int
-	values  map[int]interface{}
+// As returns an Expression that gives expr the output name alias in the
+// SELECT list of a query, such as COUNT(*) AS "total".
+func As(expr Expression, alias string) aliasedExpr {
+	return aliasedExpr{expr, alias}
 }
 
-func (p *Params) Add(value interface{}) string {
-	marker := p.next()
-	p.values[p.counter] = value
-	return marker
+type aliasedExpr struct {
+	expr  Expression
+	alias string
 }
 
-func (p *Params) New() string {
-	return p.next()
+func (a aliasedExpr) ToSQLExpr(p *Params) string {
+	return fmt.Sprintf("%s AS %s", p.Expr("expr", a.expr), pq.QuoteIdentifier(a.alias))
 }
 
-func (p *Params) next() string {
-	p.counter++
-	return fmt.Sprintf("$%d", p.counter)
-}
-
-func (p *Params) Values(inputs []interface{}) []interface{} {
-	var values []interface{}
-
-	for i := 1; i <= p.counter; i++ {
-		if val, ok := p.values[i]; ok {
-			values = append(values, val)
-		} else if len(inputs) > 0 {
-			values = append(values, inputs[0])
-			inputs = inputs[1:]
-		}
-	}
-
-	return values
-}
-
-func (p *Params) Reset() {
-	if p.counter > 0 {
-		p.counter = 0
-		p.values = make(map[int]interface{})
-	}
+func (a aliasedExpr) Relations() []string {
+	return a.expr.Relations()
 }
 
 // StringLiteral returns a text literal that will be replaced with str
@@ -408,39 +712,184 @@ type stringLiteral string
 
 func (s stringLiteral) ToSQLExpr(params *Params) string {
 	marker := params.Add(string(s))
-	return fmt.Sprintf("%s::%s", marker, textType)
+	return fmt.Sprintf("%s::%s", marker, TextType())
 }
 
 func (stringLiteral) Relations() []string {
 	return nil
 }
 
-type dataType int
+// StringParam returns a free (unbound) parameter using the "text" type.
+func StringParam() freeParam {
+	return freeParam{TextType()}
+}
 
-const (
-	textType dataType = iota
-)
+// IntParam returns a free (unbound) parameter using the "integer" type.
+func IntParam() freeParam {
+	return freeParam{IntType()}
+}
 
-func (d dataType) String() string {
-	switch d {
-	case textType:
-		return "text"
-	default:
-		panic("unknown dataType")
+// BigIntParam returns a free (unbound) parameter using the "bigint" type.
+func BigIntParam() freeParam {
+	return freeParam{BigIntType()}
+}
+
+// FloatParam returns a free (unbound) parameter using the "double precision" type.
+func FloatParam() freeParam {
+	return freeParam{FloatType()}
+}
+
+// NumericParam returns a free (unbound) parameter using the "numeric" type.
+func NumericParam() freeParam {
+	return freeParam{NumericType()}
+}
+
+// BoolParam returns a free (unbound) parameter using the "boolean" type.
+func BoolParam() freeParam {
+	return freeParam{BoolType()}
+}
+
+// TimestampParam returns a free (unbound) parameter using the "timestamp" type.
+func TimestampParam() freeParam {
+	return freeParam{TimestampType()}
+}
+
+// TimestampTZParam returns a free (unbound) parameter using the "timestamptz" type.
+func TimestampTZParam() freeParam {
+	return freeParam{TimestampTZType()}
+}
+
+// DateParam returns a free (unbound) parameter using the "date" type.
+func DateParam() freeParam {
+	return freeParam{DateType()}
+}
+
+// UUIDParam returns a free (unbound) parameter using the "uuid" type.
+func UUIDParam() freeParam {
+	return freeParam{UUIDType()}
+}
+
+// ByteaParam returns a free (unbound) parameter using the "bytea" type.
+func ByteaParam() freeParam {
+	return freeParam{ByteaType()}
+}
+
+// JSONBParam returns a free (unbound) parameter using the "jsonb" type.
+func JSONBParam() freeParam {
+	return freeParam{JSONBType()}
+}
+
+// StringArrayParam returns a free (unbound) parameter using the "text[]"
+// type. Like all array parameters, its value must be wrapped with pq.Array
+// when the query is executed.
+func StringArrayParam() freeParam {
+	return freeParam{ArrayOf(TextType())}
+}
+
+// IntArrayParam returns a free (unbound) parameter using the "integer[]" type.
+func IntArrayParam() freeParam {
+	return freeParam{ArrayOf(IntType())}
+}
+
+// BigIntArrayParam returns a free (unbound) parameter using the "bigint[]" type.
+func BigIntArrayParam() freeParam {
+	return freeParam{ArrayOf(BigIntType())}
+}
+
+// FloatArrayParam returns a free (unbound) parameter using the "double precision[]" type.
+func FloatArrayParam() freeParam {
+	return freeParam{ArrayOf(FloatType())}
+}
+
+// NumericArrayParam returns a free (unbound) parameter using the "numeric[]" type.
+func NumericArrayParam() freeParam {
+	return freeParam{ArrayOf(NumericType())}
+}
+
+// BoolArrayParam returns a free (unbound) parameter using the "boolean[]" type.
+func BoolArrayParam() freeParam {
+	return freeParam{ArrayOf(BoolType())}
+}
+
+// TimestampArrayParam returns a free (unbound) parameter using the "timestamp[]" type.
+func TimestampArrayParam() freeParam {
+	return freeParam{ArrayOf(TimestampType())}
+}
+
+// TimestampTZArrayParam returns a free (unbound) parameter using the "timestamptz[]" type.
+func TimestampTZArrayParam() freeParam {
+	return freeParam{ArrayOf(TimestampTZType())}
+}
+
+// DateArrayParam returns a free (unbound) parameter using the "date[]" type.
+func DateArrayParam() freeParam {
+	return freeParam{ArrayOf(DateType())}
+}
+
+// UUIDArrayParam returns a free (unbound) parameter using the "uuid[]" type.
+func UUIDArrayParam() freeParam {
+	return freeParam{ArrayOf(UUIDType())}
+}
+
+// ByteaArrayParam returns a free (unbound) parameter using the "bytea[]" type.
+func ByteaArrayParam() freeParam {
+	return freeParam{ArrayOf(ByteaType())}
+}
+
+// JSONBArrayParam returns a free (unbound) parameter using the "jsonb[]" type.
+func JSONBArrayParam() freeParam {
+	return freeParam{ArrayOf(JSONBType())}
+}
+
+// Param returns a named free (unbound) parameter of the given type. All
+// occurrences of the same name within a query refer to the same parameter,
+// whose value is supplied by BindNamed or BindStruct.
+func Param(name string, t DataType) namedParam {
+	return namedParam{name: name, dataType: t}
+}
+
+type namedParam struct {
+	name     string
+	dataType DataType
+
+	// err, if not empty, is reported when the parameter is converted to SQL.
+	err string
+}
+
+func (n namedParam) ToSQLExpr(params *Params) string {
+	if n.err != "" {
+		params.Errorf("%s", n.err)
 	}
+
+	// A parameter whose type could not be determined, such as one for a
+	// struct field of a type that has no obvious PostgreSQL equivalent, is
+	// left for the database to infer from the context.
+	if n.dataType == unknownType {
+		return params.Named(n.name, n.dataType)
+	}
+
+	params.checkType(n.dataType)
+	return fmt.Sprintf("%s::%s", params.Named(n.name, n.dataType), n.dataType)
 }
 
-// StringParam returns a free (unbound) parameter using the "text" type.
-func StringParam() freeParam {
-	return freeParam{textType}
+func (n namedParam) Relations() []string {
+	return nil
+}
+
+// FreeParam returns a free (unbound) parameter that is cast to the given
+// type, which may be any type built with the constructors in this package,
+// such as Numeric(10, 2) or ArrayOf(NamedType("mood")).
+func FreeParam(t DataType) freeParam {
+	return freeParam{t}
 }
 
 type freeParam struct {
-	dataType dataType
+	dataType DataType
 }
 
 func (p freeParam) ToSQLExpr(params *Params) string {
-	return fmt.Sprintf("%s::%s", params.New(), p.dataType)
+	params.checkType(p.dataType)
+	return fmt.Sprintf("%s::%s", params.newFree(p.dataType), p.dataType)
 }
 
 func (p freeParam) Relations() []string {
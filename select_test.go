@@ -1,8 +1,10 @@
 package psql
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSelectQuerySQL(t *testing.T) {
@@ -79,6 +81,54 @@ func TestSelectQuerySQL(t *testing.T) {
 			),
 			`SELECT "users".*, "animals".* FROM "users", "animals"`,
 		},
+		{
+			Select(
+				TableColumn("analytics.events", "name"),
+				AllColumns("analytics.events"),
+			),
+			`SELECT "name", "analytics"."events".* FROM "analytics"."events"`,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).From(
+				Table("users"),
+				Table("analytics.events"),
+			).Where(
+				Eq(TableColumn("users", "id"), TableColumn("analytics.events", "user_id")),
+			),
+			`SELECT "users"."name" FROM "users", "analytics"."events" WHERE ("users"."id" = "analytics"."events"."user_id")`,
+		},
+		{
+			Select(
+				SchemaTable("", "events.2024").Column("name").Qualified(),
+				SchemaTable("archive", "events.2023").Column("name").Qualified(),
+			),
+			`SELECT "events.2024"."name", "archive"."events.2023"."name" FROM "events.2024", "archive"."events.2023"`,
+		},
+		{
+			Select(
+				CountAll(),
+				Count(TableColumn("users", "email")),
+			).From(
+				Table("users"),
+			),
+			`SELECT COUNT(*), COUNT("email") FROM "users"`,
+		},
+		{
+			Select(
+				Now(),
+			).From(
+				Table("users"),
+			),
+			`SELECT now() FROM "users"`,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).From(),
+			`SELECT "name"`,
+		},
 		{
 			Select(
 				TableColumn("users", "name"),
@@ -112,6 +162,23 @@ func TestSelectQuerySQL(t *testing.T) {
 			),
 			`SELECT "name" FROM "users" ORDER BY (10 / 5) DESC`,
 		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).OrderBy(
+				Ascending(TableColumn("users", "email")).NullsFirst(),
+				Descending(TableColumn("users", "height")).NullsLast(),
+			),
+			`SELECT "name" FROM "users" ORDER BY "email" ASC NULLS FIRST, "height" DESC NULLS LAST`,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).OrderBy(
+				Ascending(TableColumn("users", "name")).Collate("en-US-x-icu").NullsLast(),
+			),
+			`SELECT "name" FROM "users" ORDER BY "name" COLLATE "en-US-x-icu" ASC NULLS LAST`,
+		},
 		{
 			Select(
 				Avg(TableColumn("users", "height")),
@@ -156,6 +223,40 @@ func TestSelectQuerySQL(t *testing.T) {
 			),
 			`SELECT "height" FROM "users" WHERE ("name" = $1::text) AND ("city" <> $2::text) AND "height" IS NOT NULL`,
 		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).Where(
+				Or(
+					And(
+						Eq(TableColumn("users", "city"), StringParam()),
+						GreaterThan(TableColumn("users", "height"), IntLiteral(180)),
+					),
+					Not(IsNull(TableColumn("users", "email"))),
+				),
+			),
+			`SELECT "name" FROM "users" WHERE ((("city" = $1::text) AND ("height" > 180)) OR (NOT "email" IS NULL))`,
+		},
+		{
+			Select(
+				And(),
+				Or(),
+				And(Eq(IntLiteral(1), IntLiteral(1))),
+			),
+			`SELECT TRUE, FALSE, (1 = 1)`,
+		},
+		{
+			Select(
+				TableColumn("users", "name"),
+			).Where(
+				GreaterThan(TableColumn("users", "height"), IntParam()),
+				LessThan(TableColumn("users", "signup_date"), DateParam()),
+				NotEq(TableColumn("users", "id"), UUIDParam()),
+				Eq(TableColumn("users", "tags"), StringArrayParam()),
+				NotEq(TableColumn("users", "scores"), FloatArrayParam()),
+			),
+			`SELECT "name" FROM "users" WHERE ("height" > $1::integer) AND ("signup_date" < $2::date) AND ("id" <> $3::uuid) AND ("tags" = $4::text[]) AND ("scores" <> $5::double precision[])`,
+		},
 		{
 			Select(
 				Now(),
@@ -217,6 +318,36 @@ func TestSelectQueryBindings(t *testing.T) {
 			`SELECT $1::text, $2::text`,
 			[]interface{}{"Hello", "Joe"},
 		},
+		{
+			Select(
+				FloatLiteral(1.5),
+				BoolLiteral(true),
+				TimeLiteral(time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)),
+				DurationLiteral(90*time.Minute+1500*time.Nanosecond),
+				BytesLiteral([]byte("abc")),
+				UUIDLiteral("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+				DecimalLiteral(big.NewRat(1, 8)),
+				DecimalLiteral(big.NewRat(2, 3)),
+				DecimalLiteral(big.NewRat(-21, 20)),
+				DecimalLiteral(big.NewRat(3, 1)),
+				NullLiteral(IntType()),
+			),
+			[]interface{}{},
+
+			`SELECT $1::double precision, $2::boolean, $3::timestamptz, $4::interval, $5::bytea, $6::uuid, $7::numeric, $8::numeric, $9::numeric, $10::numeric, NULL::integer`,
+			[]interface{}{
+				1.5,
+				true,
+				time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+				"5400000001 microseconds",
+				[]byte("abc"),
+				"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+				"0.125",
+				"0.66666666666666666667",
+				"-1.05",
+				"3",
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -232,6 +363,81 @@ func TestSelectQueryBindings(t *testing.T) {
 	}
 }
 
+func TestSelectQueryBindNamed(t *testing.T) {
+	query := Select(
+		TableColumn("users", "name"),
+		StringLiteral("Hello"),
+	).Where(
+		Eq(TableColumn("users", "id"), Param("user_id", IntType())),
+		Or(
+			Eq(TableColumn("users", "manager_id"), Param("user_id", IntType())),
+			Eq(TableColumn("users", "city"), Param("city", TextType())),
+		),
+	)
+
+	sql := `SELECT "name", $1::text FROM "users" WHERE ("id" = $2::integer) AND (("manager_id" = $2::integer) OR ("city" = $3::text))`
+	if got := query.ToSQL(); got != sql {
+		t.Errorf("expected %q, got %q", sql, got)
+	}
+
+	want := []interface{}{"Hello", 42, "London"}
+
+	bindings, err := query.BindNamed(map[string]interface{}{
+		"user_id": 42,
+		"city":    "London",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(bindings, want) {
+		t.Errorf("expected %v, got %v", want, bindings)
+	}
+
+	type location struct {
+		City string `psql:"city"`
+	}
+
+	bindings, err = query.BindStruct(&struct {
+		location
+		UserID int `psql:"user_id"`
+		Other  string
+	}{location{"London"}, 42, "ignored"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(bindings, want) {
+		t.Errorf("expected %v, got %v", want, bindings)
+	}
+
+	_, err = query.BindStruct(struct {
+		location
+		UserID int    `psql:"user_id"`
+		Email  string `psql:"email"`
+		Phone  string `psql:"phone"`
+	}{location{"London"}, 42, "joe@example.com", "555-0100"})
+	if msg := `psql: fields tagged "email", "phone" do not correspond to any named parameter`; err == nil || err.Error() != msg {
+		t.Errorf("expected error %q, got %v", msg, err)
+	}
+
+	errCases := []map[string]interface{}{
+		{"user_id": 42},
+		{"user_id": 42, "city": "London", "email": "joe@example.com"},
+	}
+
+	for i, args := range errCases {
+		if _, err := query.BindNamed(args); err == nil {
+			t.Errorf("error case %d: expected an error, got nil", i+1)
+		}
+	}
+
+	mixed := Select(TableColumn("users", "name")).Where(
+		Eq(TableColumn("users", "id"), Param("user_id", IntType())),
+		Eq(TableColumn("users", "city"), StringParam()),
+	)
+
+	if _, err := mixed.BindNamed(map[string]interface{}{"user_id": 42}); err == nil {
+		t.Error("expected an error for positional parameters, got nil")
+	}
+}
+
 func TestSelectQueryIdempotence(t *testing.T) {
 	cases := []SelectQuery{
 		Select(
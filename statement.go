@@ -0,0 +1,171 @@
+package psql
+
+import "fmt"
+
+// A Statement is the result of compiling a query: its SQL representation
+// together with the arguments it must be executed with. A Statement holds
+// no references to the query it was compiled from, and none of its methods
+// modify it, so it can be shared freely between goroutines.
+type Statement struct {
+	// SQL is the text of the query, with parameters such as $1 in place
+	// of all values.
+	SQL string
+
+	// Args holds the value of each parameter, in order. The entries for
+	// free parameters are nil, and must be filled in with one of the
+	// binding methods before the statement is executed.
+	Args []interface{}
+
+	// FreeParams describes each free parameter, in order.
+	FreeParams []ParamInfo
+}
+
+// Bindings returns a slice of arguments that can be unpacked and passed
+// into the Query and QueryRow methods of the database/sql package, using
+// inputs to replace the statement's positional free parameters in order.
+// Extra inputs are ignored, and missing ones are left out.
+func (st Statement) Bindings(inputs ...interface{}) []interface{} {
+	var values []interface{}
+	free := st.freeParams()
+
+	for i, arg := range st.Args {
+		if info, ok := free[i+1]; !ok {
+			values = append(values, arg)
+		} else if info.Name != "" {
+			continue
+		} else if len(inputs) > 0 {
+			values = append(values, inputs[0])
+			inputs = inputs[1:]
+		}
+	}
+
+	return values
+}
+
+// CheckedBindings is like Bindings, but returns an *ArgCountError if the
+// number of inputs differs from the number of positional free parameters.
+func (st Statement) CheckedBindings(inputs ...interface{}) ([]interface{}, error) {
+	return st.checkedBindings(inputs, false)
+}
+
+// StrictBindings is like CheckedBindings, but also returns an *ArgTypeError
+// if the Go type of any input is incompatible with the type of the free
+// parameter it replaces.
+func (st Statement) StrictBindings(inputs ...interface{}) ([]interface{}, error) {
+	return st.checkedBindings(inputs, true)
+}
+
+func (st Statement) checkedBindings(inputs []interface{}, strict bool) ([]interface{}, error) {
+	var names, free []ParamInfo
+	for _, info := range st.FreeParams {
+		if info.Name != "" {
+			names = append(names, info)
+		} else {
+			free = append(free, info)
+		}
+	}
+
+	if len(names) > 0 {
+		return nil, fmt.Errorf("psql: named parameters %s cannot be bound by position", quoteNames(paramNames(names)))
+	}
+
+	if len(inputs) != len(free) {
+		return nil, &ArgCountError{Params: free, Got: len(inputs)}
+	}
+
+	if strict {
+		for i, info := range free {
+			if !compatible(info, inputs[i]) {
+				return nil, &ArgTypeError{Param: info, Value: inputs[i]}
+			}
+		}
+	}
+
+	return st.Bindings(inputs...), nil
+}
+
+// BindNamed returns a slice of arguments using the values in args to
+// replace the statement's named free parameters. An error is returned if a
+// name is missing from args, if args contains an unknown name, or if the
+// statement has positional free parameters.
+func (st Statement) BindNamed(args map[string]interface{}) ([]interface{}, error) {
+	var missing, extra []string
+	known := make(map[string]bool)
+
+	for _, info := range st.FreeParams {
+		if info.Name == "" {
+			return nil, fmt.Errorf("psql: positional parameter $%d cannot be bound by name", info.Position)
+		}
+		known[info.Name] = true
+		if _, ok := args[info.Name]; !ok {
+			missing = append(missing, info.Name)
+		}
+	}
+
+	for name := range args {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("psql: missing values for named parameters %s", quoteNames(missing))
+	}
+
+	if len(extra) > 0 {
+		return nil, fmt.Errorf("psql: unknown named parameters %s", quoteNames(extra))
+	}
+
+	values := make([]interface{}, len(st.Args))
+	copy(values, st.Args)
+
+	for _, info := range st.FreeParams {
+		values[info.Position-1] = args[info.Name]
+	}
+
+	return values, nil
+}
+
+// BindStruct is like BindNamed, but takes the values of named parameters
+// from the tagged fields of the struct v. An error is returned if a tagged
+// field does not correspond to any parameter.
+func (st Statement) BindStruct(v interface{}) ([]interface{}, error) {
+	args, err := structValues(v)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, info := range st.FreeParams {
+		known[info.Name] = true
+	}
+
+	var extra []string
+	for name := range args {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+
+	if len(extra) > 0 {
+		return nil, fmt.Errorf("psql: fields tagged %s do not correspond to any named parameter", quoteNames(extra))
+	}
+
+	return st.BindNamed(args)
+}
+
+func (st Statement) freeParams() map[int]ParamInfo {
+	free := make(map[int]ParamInfo, len(st.FreeParams))
+	for _, info := range st.FreeParams {
+		free[info.Position] = info
+	}
+	return free
+}
+
+func paramNames(infos []ParamInfo) []string {
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
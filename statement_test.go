@@ -0,0 +1,76 @@
+package psql
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSelectQueryCompile(t *testing.T) {
+	query := Select(
+		TableColumn("users", "name"),
+		StringLiteral("Hello"),
+	).Where(
+		Eq(TableColumn("users", "city"), StringParam()),
+		GreaterThan(TableColumn("users", "height"), IntParam()),
+	)
+
+	want := Statement{
+		SQL:  `SELECT "name", $1::text FROM "users" WHERE ("city" = $2::text) AND ("height" > $3::integer)`,
+		Args: []interface{}{"Hello", nil, nil},
+		FreeParams: []ParamInfo{
+			{Position: 2, Type: TextType()},
+			{Position: 3, Type: IntType()},
+		},
+	}
+
+	st := query.Compile()
+	if !reflect.DeepEqual(st, want) {
+		t.Errorf("expected %+v, got %+v", want, st)
+	}
+
+	bindings := st.Bindings("London", 180)
+	if want := []interface{}{"Hello", "London", 180}; !reflect.DeepEqual(bindings, want) {
+		t.Errorf("expected %v, got %v", want, bindings)
+	}
+
+	// Binding arguments must not modify the Statement.
+	if want := []interface{}{"Hello", nil, nil}; !reflect.DeepEqual(st.Args, want) {
+		t.Errorf("expected %v, got %v", want, st.Args)
+	}
+}
+
+func TestSelectQueryConcurrentCompile(t *testing.T) {
+	base := Select(
+		TableColumn("users", "name"),
+	).Where(
+		Eq(TableColumn("users", "city"), StringParam()),
+	)
+
+	queries := []SelectQuery{
+		base,
+		base.OrderBy(Ascending(TableColumn("users", "name"))),
+		Select(StringLiteral("Hello")).Where(
+			Eq(TableColumn("users", "city"), StringLiteral("London")),
+		),
+	}
+
+	var wg sync.WaitGroup
+	for _, query := range queries {
+		want := query.Compile()
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(query SelectQuery, want Statement) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					if got := query.Compile(); !reflect.DeepEqual(got, want) {
+						t.Errorf("expected %+v, got %+v", want, got)
+						return
+					}
+				}
+			}(query, want)
+		}
+	}
+	wg.Wait()
+}
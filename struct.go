@@ -0,0 +1,190 @@
+package psql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structField describes an exported struct field tagged with `psql:"..."`.
+// The tag's first element is the field's name in SQL; any subsequent
+// comma-separated elements are options, such as "pk" or "omitempty".
+type structField struct {
+	name    string
+	index   []int
+	options []string
+
+	// goName and typ are the name and type of the field in Go.
+	goName string
+	typ    reflect.Type
+}
+
+func (f structField) hasOption(opt string) bool {
+	for _, o := range f.options {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// option returns the value of the option of the form key=value, such as
+// "type=uuid", and reports whether the field has one.
+func (f structField) option(key string) (string, bool) {
+	for _, o := range f.options {
+		if strings.HasPrefix(o, key+"=") {
+			return o[len(key)+1:], true
+		}
+	}
+	return "", false
+}
+
+// structFields returns the tagged fields of the struct type t, including
+// those of embedded structs. Fields tagged with `psql:"-"` are skipped.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("psql")
+
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, sf := range structFields(ft) {
+					sf.index = append([]int{i}, sf.index...)
+					fields = append(fields, sf)
+				}
+			}
+			continue
+		}
+
+		if !tagged || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		parts := splitTag(tag)
+		fields = append(fields, structField{
+			name:    parts[0],
+			index:   []int{i},
+			options: parts[1:],
+			goName:  f.Name,
+			typ:     f.Type,
+		})
+	}
+
+	return fields
+}
+
+// dominantFields is like structFields, but follows Go's rules for embedded
+// fields when more than one field has the same name: the shallowest field
+// shadows the others, and it is an error for there to be more than one at
+// the shallowest depth.
+func dominantFields(t reflect.Type) ([]structField, error) {
+	fields := structFields(t)
+
+	// shallowest maps each name to the position in fields of the first of
+	// the shallowest fields with that name.
+	shallowest := make(map[string]int)
+	for i, f := range fields {
+		j, ok := shallowest[f.name]
+		if !ok || len(f.index) < len(fields[j].index) {
+			shallowest[f.name] = i
+		}
+	}
+
+	var dominant []structField
+	for i, f := range fields {
+		j := shallowest[f.name]
+		if len(f.index) > len(fields[j].index) {
+			continue
+		}
+		if i != j {
+			return nil, fmt.Errorf("psql: fields %s and %s of %s are both tagged %q", fields[j].goName, f.goName, t, f.name)
+		}
+		dominant = append(dominant, f)
+	}
+
+	return dominant, nil
+}
+
+// splitTag splits a struct tag on commas, except for those between
+// parentheses, as in "price,type=numeric(10,2)".
+func splitTag(tag string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range tag {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tag[start:])
+}
+
+// structValue returns the struct value held by v, dereferencing pointers.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("psql: expected a struct, got nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("psql: expected a struct, got %T", v)
+	}
+
+	return rv, nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but returns false
+// instead of panicking when it encounters a nil embedded pointer.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// structValues returns a map from the names of the tagged fields of the
+// struct v to their values.
+func structValues(v interface{}) (map[string]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := dominantFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	for _, f := range fields {
+		if fv, ok := fieldByIndex(rv, f.index); ok {
+			values[f.name] = fv.Interface()
+		}
+	}
+
+	return values, nil
+}
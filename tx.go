@@ -0,0 +1,204 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// A TxBeginner begins transactions. It is satisfied by *sql.DB and
+// *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TxOptions holds the options used by WithTx.
+type TxOptions struct {
+	// Isolation and ReadOnly are passed on to BeginTx.
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+
+	// MaxAttempts is the maximum number of times the transaction is run
+	// before giving up. If zero, DefaultMaxAttempts is used.
+	MaxAttempts int
+
+	// BaseDelay and MaxDelay bound the random delay before each retry.
+	// The upper bound on the delay starts at BaseDelay and doubles after
+	// every attempt, up to MaxDelay. If zero, DefaultBaseDelay and
+	// DefaultMaxDelay are used.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// The defaults used by WithTx when the corresponding TxOptions fields are
+// zero.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBaseDelay   = 10 * time.Millisecond
+	DefaultMaxDelay    = time.Second
+)
+
+// Tx is a transaction started by WithTx.
+type Tx struct {
+	*sql.Tx
+
+	// depth is the number of savepoints enclosing the transaction.
+	depth int
+}
+
+// WithTx runs fn inside a transaction begun on db with the given options,
+// which may be nil. The transaction is committed if fn returns nil, and
+// rolled back if it returns an error or panics.
+//
+// If fn or the commit fails with a serialization failure (SQLSTATE 40001)
+// or a deadlock (SQLSTATE 40P01), the whole transaction is retried after a
+// random delay, up to opts.MaxAttempts times. Since fn may be run more than
+// once, it should not have side effects outside the transaction.
+func WithTx(ctx context.Context, db TxBeginner, opts *TxOptions, fn func(*Tx) error) error {
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, opts.delay(attempt)); err != nil {
+				return err
+			}
+		}
+
+		err = runTx(ctx, db, opts, fn)
+		if !retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func runTx(ctx context.Context, db TxBeginner, opts *TxOptions, fn func(*Tx) error) error {
+	sqlTx, err := db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: opts.Isolation,
+		ReadOnly:  opts.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: sqlTx}); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+// delay returns a random duration to wait before the given attempt.
+func (opts *TxOptions) delay(attempt int) time.Duration {
+	base, max := opts.BaseDelay, opts.MaxDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	if max <= 0 {
+		max = DefaultMaxDelay
+	}
+
+	ceil := base
+	for i := 1; i < attempt && ceil < max; i++ {
+		ceil *= 2
+	}
+	if ceil > max {
+		ceil = max
+	}
+
+	return time.Duration(rand.Int63n(int64(ceil) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryable reports whether err was caused by a serialization failure or
+// a deadlock, after which a transaction may succeed if it is retried.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+// Savepoint runs fn inside a savepoint. If fn returns an error or panics,
+// the transaction is rolled back to the savepoint, undoing only the work
+// done by fn; otherwise, the savepoint is released. Savepoints may be
+// nested by calling Savepoint on the Tx passed to fn.
+func (tx *Tx) Savepoint(ctx context.Context, fn func(*Tx) error) error {
+	name := pq.QuoteIdentifier(fmt.Sprintf("psql_savepoint_%d", tx.depth+1))
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			// The panic is more important than any error from the
+			// rollback, which is discarded.
+			tx.rollbackTo(ctx, name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: tx.Tx, depth: tx.depth + 1}); err != nil {
+		// If the rollback fails, the transaction is unusable and the
+		// failure will surface when it is committed, so fn's error is
+		// the more useful one to return.
+		tx.rollbackTo(ctx, name)
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// rollbackTo rolls the transaction back to the savepoint with the given
+// name, then releases it. ROLLBACK TO SAVEPOINT leaves the savepoint in
+// place, so without the release every failed attempt would leave another
+// open savepoint behind until the end of the transaction.
+func (tx *Tx) rollbackTo(ctx context.Context, name string) error {
+	if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
@@ -0,0 +1,147 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestWithTx(t *testing.T) {
+	failures := map[string]int{}
+
+	db, fake := openFakeDB(func(query string, _ []driver.Value) (*fakeRows, error) {
+		if n := failures[query]; n > 0 {
+			failures[query] = n - 1
+			return nil, &pq.Error{Code: "40001", Message: "could not serialize access"}
+		}
+		return &fakeRows{}, nil
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	opts := &TxOptions{Isolation: sql.LevelSerializable, BaseDelay: time.Nanosecond}
+	update := Update("accounts").Set(Assignment{"balance", IntLiteral(0)})
+
+	cases := []struct {
+		failures map[string]int
+		fn       func(*Tx) error
+
+		err        error
+		statements []string
+	}{
+		{
+			nil,
+			func(tx *Tx) error {
+				_, err := Exec(ctx, tx, update)
+				return err
+			},
+			nil,
+			[]string{"BEGIN", `UPDATE "accounts" SET "balance" = 0`, "COMMIT"},
+		},
+		{
+			map[string]int{`UPDATE "accounts" SET "balance" = 0`: 1, "COMMIT": 1},
+			func(tx *Tx) error {
+				_, err := Exec(ctx, tx, update)
+				return err
+			},
+			nil,
+			[]string{
+				"BEGIN", `UPDATE "accounts" SET "balance" = 0`, "ROLLBACK",
+				"BEGIN", `UPDATE "accounts" SET "balance" = 0`, "COMMIT",
+				"BEGIN", `UPDATE "accounts" SET "balance" = 0`, "COMMIT",
+			},
+		},
+		{
+			nil,
+			func(tx *Tx) error {
+				return sql.ErrNoRows
+			},
+			sql.ErrNoRows,
+			[]string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			map[string]int{"BEGIN": 10},
+			func(tx *Tx) error {
+				return nil
+			},
+			&pq.Error{Code: "40001", Message: "could not serialize access"},
+			[]string{"BEGIN", "BEGIN", "BEGIN", "BEGIN", "BEGIN"},
+		},
+	}
+
+	for i, tc := range cases {
+		failures = tc.failures
+		before := len(fake.statements())
+
+		err := WithTx(ctx, db, opts, tc.fn)
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("test case %d: expected error %v, got %v", i+1, tc.err, err)
+		}
+
+		if stmts := fake.statements()[before:]; !reflect.DeepEqual(stmts, tc.statements) {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.statements, stmts)
+		}
+	}
+}
+
+func TestWithTxPanic(t *testing.T) {
+	db, fake := openFakeDB(nil)
+	defer db.Close()
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("expected panic %q, got %v", "boom", p)
+		}
+
+		expected := []string{"BEGIN", "ROLLBACK"}
+		if stmts := fake.statements(); !reflect.DeepEqual(stmts, expected) {
+			t.Errorf("expected %q, got %q", expected, stmts)
+		}
+	}()
+
+	WithTx(context.Background(), db, nil, func(*Tx) error {
+		panic("boom")
+	})
+}
+
+func TestSavepoint(t *testing.T) {
+	db, fake := openFakeDB(nil)
+	defer db.Close()
+
+	ctx := context.Background()
+	errInner := errors.New("inner")
+
+	err := WithTx(ctx, db, nil, func(tx *Tx) error {
+		return tx.Savepoint(ctx, func(tx *Tx) error {
+			if err := tx.Savepoint(ctx, func(*Tx) error { return errInner }); err != errInner {
+				t.Errorf("expected %v, got %v", errInner, err)
+			}
+			return tx.Savepoint(ctx, func(*Tx) error { return nil })
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"BEGIN",
+		`SAVEPOINT "psql_savepoint_1"`,
+		`SAVEPOINT "psql_savepoint_2"`,
+		`ROLLBACK TO SAVEPOINT "psql_savepoint_2"`,
+		`RELEASE SAVEPOINT "psql_savepoint_2"`,
+		`SAVEPOINT "psql_savepoint_2"`,
+		`RELEASE SAVEPOINT "psql_savepoint_2"`,
+		`RELEASE SAVEPOINT "psql_savepoint_1"`,
+		"COMMIT",
+	}
+
+	if stmts := fake.statements(); !reflect.DeepEqual(stmts, expected) {
+		t.Errorf("expected %q, got %q", expected, stmts)
+	}
+}
@@ -0,0 +1,52 @@
+package typed
+
+import "github.com/leocassarani/psql"
+
+// Avg returns an Expr representing a call to the AVG aggregate function
+// with the column col as an argument.
+func Avg[T Number](col Column[T]) Expr[float64] {
+	return expr[float64]{psql.Avg(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// Count returns an Expr representing a call to the COUNT aggregate function
+// with the column col as an argument.
+func Count[T any](col Column[T]) Expr[int64] {
+	return expr[int64]{psql.Count(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// CountAll returns an Expr representing COUNT(*).
+func CountAll() Expr[int64] {
+	return expr[int64]{psql.CountAll()}
+}
+
+// Max returns an Expr representing a call to the MAX aggregate function
+// with the column col as an argument.
+func Max[T Ordered](col Column[T]) Expr[T] {
+	return expr[T]{psql.Max(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// Min returns an Expr representing a call to the MIN aggregate function
+// with the column col as an argument.
+func Min[T Ordered](col Column[T]) Expr[T] {
+	return expr[T]{psql.Min(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// Sum returns an Expr representing a call to the SUM aggregate function
+// with the floating-point column col as an argument. The sums of integer
+// columns are wider than the columns themselves, so they are built with
+// SumInt and SumBigInt instead.
+func Sum[T ~float64](col Column[T]) Expr[T] {
+	return expr[T]{psql.Sum(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// SumInt is like Sum, but for an integer column, whose sum PostgreSQL
+// returns as a bigint.
+func SumInt[T ~int32](col Column[T]) Expr[int64] {
+	return expr[int64]{psql.Sum(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
+
+// SumBigInt is like Sum, but for a bigint column, whose sum PostgreSQL
+// returns as a numeric that may not fit in an int64.
+func SumBigInt[T ~int | ~int64](col Column[T]) Expr[Decimal] {
+	return expr[Decimal]{psql.Sum(qualify(psql.TableColumn(col.table, col.name), col.qualified))}
+}
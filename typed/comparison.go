@@ -0,0 +1,69 @@
+package typed
+
+import "github.com/leocassarani/psql"
+
+// Eq returns a Bool representing the equality comparison between a and b.
+func Eq[T any](a, b Expr[T]) Bool {
+	return boolExpr{psql.Eq(a, b)}
+}
+
+// NotEq returns a Bool representing the inequality comparison between a and b.
+func NotEq[T any](a, b Expr[T]) Bool {
+	return boolExpr{psql.NotEq(a, b)}
+}
+
+// LessThan returns a Bool representing the less-than comparison between a and b.
+func LessThan[T Ordered](a, b Expr[T]) Bool {
+	return boolExpr{psql.LessThan(a, b)}
+}
+
+// LessThanOrEq returns a Bool representing the less-than-or-equal-to
+// comparison between a and b.
+func LessThanOrEq[T Ordered](a, b Expr[T]) Bool {
+	return boolExpr{psql.LessThanOrEq(a, b)}
+}
+
+// GreaterThan returns a Bool representing the greater-than comparison
+// between a and b.
+func GreaterThan[T Ordered](a, b Expr[T]) Bool {
+	return boolExpr{psql.GreaterThan(a, b)}
+}
+
+// GreaterThanOrEq returns a Bool representing the greater-than-or-equal-to
+// comparison between a and b.
+func GreaterThanOrEq[T Ordered](a, b Expr[T]) Bool {
+	return boolExpr{psql.GreaterThanOrEq(a, b)}
+}
+
+// IsNull returns a Bool comparing expr and NULL for equality.
+func IsNull[T any](expr Expr[T]) Bool {
+	return boolExpr{psql.IsNull(expr)}
+}
+
+// IsNotNull returns a Bool comparing expr and NULL for inequality.
+func IsNotNull[T any](expr Expr[T]) Bool {
+	return boolExpr{psql.IsNotNull(expr)}
+}
+
+// And returns a Bool that is true when all of the given Bools are true.
+func And(exprs ...Bool) Bool {
+	return boolExpr{psql.And(booleans(exprs)...)}
+}
+
+// Or returns a Bool that is true when at least one of the given Bools is true.
+func Or(exprs ...Bool) Bool {
+	return boolExpr{psql.Or(booleans(exprs)...)}
+}
+
+// Not returns a Bool that is true when expr is false.
+func Not(expr Bool) Bool {
+	return boolExpr{psql.Not(expr)}
+}
+
+func booleans(exprs []Bool) []psql.BooleanExpression {
+	bools := make([]psql.BooleanExpression, len(exprs))
+	for i, expr := range exprs {
+		bools[i] = expr
+	}
+	return bools
+}
@@ -0,0 +1,34 @@
+package typed
+
+import "github.com/leocassarani/psql"
+
+// Plus returns an Expr representing the addition of a and b.
+func Plus[T Number](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Plus(a, b)}
+}
+
+// Minus returns an Expr representing the subtraction of b from a.
+func Minus[T Number](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Minus(a, b)}
+}
+
+// Times returns an Expr representing the multiplication of a and b.
+func Times[T Number](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Times(a, b)}
+}
+
+// Divide returns an Expr representing the division of a and b. As in
+// PostgreSQL, the division of integers truncates the result.
+func Divide[T Number](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Divide(a, b)}
+}
+
+// Modulo returns an Expr representing the modulo of a and b.
+func Modulo[T Integer](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Modulo(a, b)}
+}
+
+// Pow returns an Expr representing the exponentiation of a and b.
+func Pow[T Number](a, b Expr[T]) Expr[T] {
+	return expr[T]{psql.Pow(a, b)}
+}
@@ -0,0 +1,203 @@
+// Package typed provides a layer of statically typed expressions on top of
+// package psql. Each expression carries the Go type corresponding to its
+// SQL type, so that comparing an integer column with a text parameter, for
+// example, is a compile-time error rather than a PostgreSQL one:
+//
+//	age := typed.TableColumn[int64]("users", "age")
+//
+//	psql.Select(age).Where(
+//		typed.GreaterThan(age, typed.FreeParam[int64]()),
+//	)
+//
+// Every typed expression implements psql.Expression, and every boolean one
+// implements psql.BooleanExpression, so they can be used anywhere in a query
+// alongside untyped expressions.
+package typed
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/leocassarani/psql"
+)
+
+// Expr is an expression whose SQL type corresponds to the Go type T.
+type Expr[T any] interface {
+	psql.Expression
+
+	// sqlType is never called. It only ties the expression to T, and
+	// prevents Expr from being implemented outside this package.
+	sqlType(T)
+}
+
+// Bool is a boolean expression, which can be used in a WHERE clause.
+type Bool interface {
+	Expr[bool]
+	psql.BooleanExpression
+}
+
+// Decimal is the Go type of SQL numeric values, such as the result of
+// SumBigInt. It holds the value's decimal representation, so that no
+// precision is lost when scanning it.
+type Decimal string
+
+// Integer is the set of Go integer types that can be used as SQL integers.
+type Integer interface {
+	~int | ~int32 | ~int64
+}
+
+// Number is the set of Go types that can be used as SQL numbers.
+type Number interface {
+	Integer | ~float64
+}
+
+// Ordered is the set of Go types whose SQL values can be compared with
+// operators such as < and >=.
+type Ordered interface {
+	Number | ~string | time.Time
+}
+
+// Scalar is the set of Go types for which there is a corresponding SQL type
+// that parameters can be cast to.
+type Scalar interface {
+	Ordered | ~bool | ~[]byte
+}
+
+type expr[T any] struct {
+	psql.Expression
+}
+
+func (expr[T]) sqlType(T) {}
+
+type boolExpr struct {
+	psql.BooleanExpression
+}
+
+func (boolExpr) sqlType(bool) {}
+
+// Column is a table column holding values of type T.
+type Column[T any] struct {
+	table, name string
+	qualified   bool
+	outer       bool
+}
+
+// TableColumn returns a Column representing the column col of the table
+// with the given name, which may be schema-qualified.
+func TableColumn[T any](name, col string) Column[T] {
+	return Column[T]{table: name, name: col}
+}
+
+// Qualified returns a copy of c that is always rendered together with the
+// name of its table, as in "users"."id".
+func (c Column[T]) Qualified() Column[T] {
+	c.qualified = true
+	return c
+}
+
+// Outer returns a copy of c that refers to a relation of an outer query, as
+// with the Outer method of the columns returned by psql.TableColumn.
+func (c Column[T]) Outer() Column[T] {
+	c.outer = true
+	return c
+}
+
+func (c Column[T]) ToSQLExpr(p *psql.Params) string {
+	if c.outer {
+		return psql.TableColumn(c.table, c.name).Outer().ToSQLExpr(p)
+	}
+	return qualify(psql.TableColumn(c.table, c.name), c.qualified).ToSQLExpr(p)
+}
+
+func (c Column[T]) Relations() []string {
+	if c.outer {
+		return nil
+	}
+	return psql.TableColumn(c.table, c.name).Relations()
+}
+
+func (Column[T]) sqlType(T) {}
+
+// qualify returns col, qualified with its table name if qualified is true.
+// It is generic so that it can accept and return the unexported column type
+// used by package psql.
+func qualify[C interface{ Qualified() C }](col C, qualified bool) C {
+	if qualified {
+		return col.Qualified()
+	}
+	return col
+}
+
+// FreeParam returns a free (unbound) parameter of type T, cast to the
+// corresponding SQL type.
+func FreeParam[T Scalar]() Expr[T] {
+	return expr[T]{psql.FreeParam(dataType[T]())}
+}
+
+// NamedParam returns a named free (unbound) parameter of type T, cast to
+// the corresponding SQL type. As with psql.Param, all occurrences of the
+// same name within a query refer to the same parameter.
+func NamedParam[T Scalar](name string) Expr[T] {
+	return expr[T]{psql.Param(name, dataType[T]())}
+}
+
+// Value returns an Expression representing the value v. As with the
+// literals in package psql, v is sent as a parameter, except for integers.
+func Value[T Scalar](v T) Expr[T] {
+	rv := reflect.ValueOf(v)
+
+	if t, ok := any(v).(time.Time); ok {
+		return expr[T]{psql.TimeLiteral(t)}
+	}
+
+	if d, ok := any(v).(Decimal); ok {
+		// A string that isn't a valid number is still cast to numeric, so
+		// that PostgreSQL reports it rather than comparing it as text.
+		if r, ok := new(big.Rat).SetString(string(d)); ok {
+			return expr[T]{psql.DecimalLiteral(r)}
+		}
+		return expr[T]{psql.Cast(psql.StringLiteral(string(d)), psql.NumericType())}
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return expr[T]{psql.IntLiteral(int(rv.Int()))}
+	case reflect.Float64:
+		return expr[T]{psql.FloatLiteral(rv.Float())}
+	case reflect.String:
+		return expr[T]{psql.StringLiteral(rv.String())}
+	case reflect.Bool:
+		return expr[T]{psql.BoolLiteral(rv.Bool())}
+	default:
+		return expr[T]{psql.BytesLiteral(rv.Bytes())}
+	}
+}
+
+// dataType returns the SQL type corresponding to the Go type T.
+func dataType[T Scalar]() psql.DataType {
+	var zero T
+	if _, ok := any(zero).(time.Time); ok {
+		return psql.TimestampTZType()
+	}
+
+	// Decimal is a string type, but holds numeric values.
+	if _, ok := any(zero).(Decimal); ok {
+		return psql.NumericType()
+	}
+
+	switch reflect.TypeOf(zero).Kind() {
+	case reflect.Int, reflect.Int64:
+		return psql.BigIntType()
+	case reflect.Int32:
+		return psql.IntType()
+	case reflect.Float64:
+		return psql.FloatType()
+	case reflect.String:
+		return psql.TextType()
+	case reflect.Bool:
+		return psql.BoolType()
+	default:
+		return psql.ByteaType()
+	}
+}
@@ -0,0 +1,108 @@
+package typed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leocassarani/psql"
+)
+
+type userID int64
+
+func TestTypedExpressions(t *testing.T) {
+	var (
+		id        = TableColumn[userID]("users", "id")
+		age       = TableColumn[int64]("users", "age")
+		name      = TableColumn[string]("users", "name")
+		height    = TableColumn[float64]("users", "height")
+		createdAt = TableColumn[time.Time]("users", "created_at")
+		visits    = TableColumn[int32]("users", "visits")
+	)
+
+	cases := []struct {
+		query psql.SelectQuery
+		sql   string
+	}{
+		{
+			psql.Select(name).Where(
+				Eq(id, FreeParam[userID]()),
+			),
+			`SELECT "name" FROM "users" WHERE ("id" = $1::bigint)`,
+		},
+		{
+			psql.Select(name).Where(
+				And(
+					GreaterThanOrEq(age, NamedParam[int64]("min_age")),
+					LessThan(createdAt, FreeParam[time.Time]()),
+					Not(IsNull(height)),
+				),
+			),
+			`SELECT "name" FROM "users" WHERE (("age" >= $1::bigint) AND ("created_at" < $2::timestamptz) AND (NOT "height" IS NULL))`,
+		},
+		{
+			psql.Select(Plus(age, Value[int64](1)), Divide(height, FreeParam[float64]())).Where(
+				Or(NotEq(name, Value("Joe")), Eq(Modulo(age, Value[int64](2)), Value[int64](0))),
+			),
+			`SELECT ("age" + 1), ("height" / $1::double precision) FROM "users" WHERE (("name" <> $2::text) OR (("age" % 2) = 0))`,
+		},
+		{
+			psql.Select(Avg(height), Max(createdAt), Count(id.Qualified()), CountAll()).GroupBy(name),
+			`SELECT AVG("height"), MAX("created_at"), COUNT("users"."id"), COUNT(*) FROM "users" GROUP BY "name"`,
+		},
+		{
+			psql.Select(Sum(height), SumInt(visits), SumBigInt(age)),
+			`SELECT SUM("height"), SUM("visits"), SUM("age") FROM "users"`,
+		},
+		{
+			psql.Select(name).Where(
+				psql.Eq(id, psql.AnySelect(psql.Select(TableColumn[userID]("bans", "user_id")).Where(
+					GreaterThan(TableColumn[time.Time]("bans", "created_at"), createdAt.Outer()),
+				))),
+			),
+			`SELECT "name" FROM "users" WHERE ("id" = ANY (SELECT "user_id" FROM "bans" WHERE ("created_at" > "users"."created_at")))`,
+		},
+	}
+
+	// The sums of integer columns are wider than the columns themselves.
+	var (
+		_ Expr[float64] = Sum(height)
+		_ Expr[int64]   = SumInt(visits)
+		_ Expr[Decimal] = SumBigInt(age)
+	)
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+}
+
+func TestFreeParamTypes(t *testing.T) {
+	cases := []struct {
+		expr psql.Expression
+		sql  string
+	}{
+		{FreeParam[int](), "SELECT $1::bigint"},
+		{FreeParam[int32](), "SELECT $1::integer"},
+		{FreeParam[float64](), "SELECT $1::double precision"},
+		{FreeParam[string](), "SELECT $1::text"},
+		{FreeParam[bool](), "SELECT $1::boolean"},
+		{FreeParam[time.Time](), "SELECT $1::timestamptz"},
+		{FreeParam[[]byte](), "SELECT $1::bytea"},
+		{FreeParam[Decimal](), "SELECT $1::numeric"},
+		{Value[Decimal]("10.50"), "SELECT $1::numeric"},
+		{GreaterThan(SumBigInt(TableColumn[int64]("orders", "amount")), FreeParam[Decimal]()), `SELECT (SUM("amount") > $1::numeric) FROM "orders"`},
+	}
+
+	for i, tc := range cases {
+		if sql := psql.Select(tc.expr).ToSQL(); sql != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, sql)
+		}
+	}
+}
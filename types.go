@@ -0,0 +1,459 @@
+package psql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A DataType identifies a PostgreSQL data type, and is used to cast
+// parameters, literals and expressions to the correct type. DataTypes are
+// comparable with ==.
+//
+// Besides the built-in types returned by functions such as TextType and
+// IntType, DataTypes can be built with type
+// modifiers, as in Numeric(10, 2) or Varchar(255), as arrays with ArrayOf,
+// or for user-defined types such as domains, enums and composite types
+// with NamedType.
+type DataType struct {
+	kind typeKind
+
+	// name is the possibly schema-qualified name of a user-defined type.
+	name string
+
+	// mods holds the first nmods type modifiers, such as the precision
+	// and scale of a numeric type.
+	mods  [2]int
+	nmods int
+
+	// dims is the number of array dimensions.
+	dims int
+}
+
+// TextType returns the text type.
+func TextType() DataType {
+	return DataType{kind: textKind}
+}
+
+// VarcharType returns the varchar type.
+func VarcharType() DataType {
+	return DataType{kind: varcharKind}
+}
+
+// SmallIntType returns the smallint type.
+func SmallIntType() DataType {
+	return DataType{kind: smallIntKind}
+}
+
+// IntType returns the integer type.
+func IntType() DataType {
+	return DataType{kind: intKind}
+}
+
+// BigIntType returns the bigint type.
+func BigIntType() DataType {
+	return DataType{kind: bigIntKind}
+}
+
+// RealType returns the real type.
+func RealType() DataType {
+	return DataType{kind: realKind}
+}
+
+// FloatType returns the double precision type.
+func FloatType() DataType {
+	return DataType{kind: floatKind}
+}
+
+// NumericType returns the numeric type.
+func NumericType() DataType {
+	return DataType{kind: numericKind}
+}
+
+// BoolType returns the boolean type.
+func BoolType() DataType {
+	return DataType{kind: boolKind}
+}
+
+// TimestampType returns the timestamp type.
+func TimestampType() DataType {
+	return DataType{kind: timestampKind}
+}
+
+// TimestampTZType returns the timestamptz type.
+func TimestampTZType() DataType {
+	return DataType{kind: timestampTZKind}
+}
+
+// DateType returns the date type.
+func DateType() DataType {
+	return DataType{kind: dateKind}
+}
+
+// TimeType returns the time type.
+func TimeType() DataType {
+	return DataType{kind: timeKind}
+}
+
+// IntervalType returns the interval type.
+func IntervalType() DataType {
+	return DataType{kind: intervalKind}
+}
+
+// UUIDType returns the uuid type.
+func UUIDType() DataType {
+	return DataType{kind: uuidKind}
+}
+
+// ByteaType returns the bytea type.
+func ByteaType() DataType {
+	return DataType{kind: byteaKind}
+}
+
+// JSONType returns the json type.
+func JSONType() DataType {
+	return DataType{kind: jsonKind}
+}
+
+// JSONBType returns the jsonb type.
+func JSONBType() DataType {
+	return DataType{kind: jsonbKind}
+}
+
+// unknownType is the type of parameters whose type is not known, such as
+// those allocated with Params.New.
+var unknownType = DataType{}
+
+// Varchar returns the type varchar(n), a string of at most n characters.
+func Varchar(n int) DataType {
+	return VarcharType().withMods(n)
+}
+
+// Char returns the type char(n), a blank-padded string of n characters.
+func Char(n int) DataType {
+	return DataType{kind: charKind}.withMods(n)
+}
+
+// Numeric returns the type numeric(precision, scale), a decimal number
+// with precision significant digits, scale of which are after the decimal
+// point.
+func Numeric(precision, scale int) DataType {
+	return NumericType().withMods(precision, scale)
+}
+
+// Timestamp returns the type timestamp(precision), a timestamp without
+// time zone with the given number of fractional digits in its seconds.
+func Timestamp(precision int) DataType {
+	return TimestampType().withMods(precision)
+}
+
+// TimestampTZ returns the type timestamptz(precision), a timestamp with
+// time zone with the given number of fractional digits in its seconds.
+func TimestampTZ(precision int) DataType {
+	return TimestampTZType().withMods(precision)
+}
+
+// ArrayOf returns the type of arrays whose elements are of type t. Calling
+// ArrayOf on an array type adds a dimension, as in integer[][].
+func ArrayOf(t DataType) DataType {
+	t.dims++
+	return t
+}
+
+// NamedType returns the user-defined type with the given name, which may
+// be schema-qualified. It can be used for domains, enums and composite
+// types, whose names are quoted as identifiers, as in "public"."mood".
+func NamedType(name string) DataType {
+	return DataType{kind: namedKind, name: name}
+}
+
+// typeNames maps the names and common aliases of the built-in types, as
+// they would appear in a struct tag, to the corresponding DataTypes.
+var typeNames = map[string]DataType{
+	"text":                        TextType(),
+	"varchar":                     VarcharType(),
+	"character varying":           VarcharType(),
+	"char":                        DataType{kind: charKind},
+	"character":                   DataType{kind: charKind},
+	"smallint":                    SmallIntType(),
+	"int2":                        SmallIntType(),
+	"integer":                     IntType(),
+	"int":                         IntType(),
+	"int4":                        IntType(),
+	"bigint":                      BigIntType(),
+	"int8":                        BigIntType(),
+	"real":                        RealType(),
+	"float4":                      RealType(),
+	"double precision":            FloatType(),
+	"float8":                      FloatType(),
+	"numeric":                     NumericType(),
+	"decimal":                     NumericType(),
+	"boolean":                     BoolType(),
+	"bool":                        BoolType(),
+	"timestamp":                   TimestampType(),
+	"timestamp without time zone": TimestampType(),
+	"timestamptz":                 TimestampTZType(),
+	"timestamp with time zone":    TimestampTZType(),
+	"date":                        DateType(),
+	"time":                        TimeType(),
+	"time without time zone":      TimeType(),
+	"interval":                    IntervalType(),
+	"uuid":                        UUIDType(),
+	"bytea":                       ByteaType(),
+	"json":                        JSONType(),
+	"jsonb":                       JSONBType(),
+}
+
+// maxMods is the number of type modifiers accepted by each kind of type.
+var maxMods = map[typeKind]int{
+	varcharKind:     1,
+	charKind:        1,
+	numericKind:     2,
+	timestampKind:   1,
+	timestampTZKind: 1,
+}
+
+// parseDataType returns the DataType with the given name, as it would be
+// written in SQL, such as "uuid", "numeric(10,2)" or "timestamp with time
+// zone". The name may be followed by any number of "[]" to denote an
+// array. Names that are not those of a built-in type must be identifiers,
+// possibly schema-qualified, and are taken to be user-defined types.
+func parseDataType(s string) (DataType, error) {
+	name := strings.TrimSpace(s)
+	dims := 0
+	for strings.HasSuffix(name, "[]") {
+		name = strings.TrimSpace(strings.TrimSuffix(name, "[]"))
+		dims++
+	}
+
+	// The modifiers usually follow the name, but precede the time zone in
+	// "timestamp(3) with time zone".
+	var mods []int
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		j := strings.IndexByte(name, ')')
+		if j < i || strings.ContainsAny(name[j+1:], "()") {
+			return DataType{}, fmt.Errorf("invalid type %q", s)
+		}
+		for _, m := range strings.Split(name[i+1:j], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(m))
+			if err != nil {
+				return DataType{}, fmt.Errorf("invalid modifier %q in type %q", strings.TrimSpace(m), s)
+			}
+			mods = append(mods, n)
+		}
+		name = name[:i] + " " + name[j+1:]
+	}
+
+	t, ok := typeNames[strings.ToLower(strings.Join(strings.Fields(name), " "))]
+	if !ok {
+		name = strings.TrimSpace(name)
+		if !isTypeName(name) {
+			return DataType{}, fmt.Errorf("invalid type %q", s)
+		}
+		t = NamedType(name)
+	}
+
+	if len(mods) > maxMods[t.kind] {
+		return DataType{}, fmt.Errorf("too many modifiers in type %q", s)
+	}
+
+	t = t.withMods(mods...)
+	t.dims = dims
+	return t, t.validate()
+}
+
+// isTypeName reports whether name is an identifier, optionally qualified
+// with the name of a schema, that can be used as the name of a
+// user-defined type.
+func isTypeName(name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return false
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+		for i, c := range part {
+			letter := c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+			if !letter && (i == 0 || !(c == '$' || ('0' <= c && c <= '9'))) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (d DataType) withMods(mods ...int) DataType {
+	d.nmods = copy(d.mods[:], mods)
+	return d
+}
+
+func (d DataType) String() string {
+	var s string
+	switch {
+	case d.kind == namedKind:
+		s = parseTable(d.name).String()
+	case d.kind == unknownKind || d.kind.valid():
+		s = d.kind.String()
+	default:
+		s = unknownEnum(d.kind)
+	}
+
+	if d.nmods > 0 {
+		mods := make([]string, d.nmods)
+		for i := range mods {
+			mods[i] = strconv.Itoa(d.mods[i])
+		}
+		s += "(" + strings.Join(mods, ",") + ")"
+	}
+
+	return s + strings.Repeat("[]", d.dims)
+}
+
+// validate returns an error if d cannot be used to cast a value, either
+// because its kind is unknown or because its modifiers are out of range.
+func (d DataType) validate() error {
+	switch d.kind {
+	case namedKind:
+		if d.name == "" {
+			return errors.New("user-defined type has an empty name")
+		}
+	case varcharKind, charKind:
+		if d.nmods > 0 && d.mods[0] < 1 {
+			return fmt.Errorf("invalid length in type %s", d)
+		}
+	case numericKind:
+		// Since PostgreSQL 15, the scale may be negative or greater than
+		// the precision, as long as it is within ±1000.
+		if d.nmods > 0 && (d.mods[0] < 1 || d.mods[0] > 1000 || d.mods[1] < -1000 || d.mods[1] > 1000) {
+			return fmt.Errorf("invalid precision or scale in type %s", d)
+		}
+	case timestampKind, timestampTZKind:
+		if d.nmods > 0 && (d.mods[0] < 0 || d.mods[0] > 6) {
+			return fmt.Errorf("invalid precision in type %s", d)
+		}
+	default:
+		if !d.kind.valid() {
+			return fmt.Errorf("unknown %s", unknownEnum(d.kind))
+		}
+	}
+	return nil
+}
+
+type typeKind int
+
+const (
+	unknownKind typeKind = iota
+	textKind
+	varcharKind
+	charKind
+	smallIntKind
+	intKind
+	bigIntKind
+	realKind
+	floatKind
+	numericKind
+	boolKind
+	timestampKind
+	timestampTZKind
+	dateKind
+	timeKind
+	intervalKind
+	uuidKind
+	byteaKind
+	jsonKind
+	jsonbKind
+	namedKind
+)
+
+func (k typeKind) String() string {
+	switch k {
+	case unknownKind:
+		return "unknown"
+	case textKind:
+		return "text"
+	case varcharKind:
+		return "varchar"
+	case charKind:
+		return "char"
+	case smallIntKind:
+		return "smallint"
+	case intKind:
+		return "integer"
+	case bigIntKind:
+		return "bigint"
+	case realKind:
+		return "real"
+	case floatKind:
+		return "double precision"
+	case numericKind:
+		return "numeric"
+	case boolKind:
+		return "boolean"
+	case timestampKind:
+		return "timestamp"
+	case timestampTZKind:
+		return "timestamptz"
+	case dateKind:
+		return "date"
+	case timeKind:
+		return "time"
+	case intervalKind:
+		return "interval"
+	case uuidKind:
+		return "uuid"
+	case byteaKind:
+		return "bytea"
+	case jsonKind:
+		return "json"
+	case jsonbKind:
+		return "jsonb"
+	case namedKind:
+		return "named"
+	default:
+		return unknownEnum(k)
+	}
+}
+
+// valid reports whether k is a built-in type that values can be cast to.
+func (k typeKind) valid() bool {
+	return k >= textKind && k <= jsonbKind
+}
+
+// Cast returns an Expression representing the conversion of expr to the
+// type t, as in CAST("price" AS numeric(10,2)).
+func Cast(expr Expression, t DataType) cast {
+	return cast{expr, t}
+}
+
+// CastBool is like Cast, but converts expr to boolean, and the result is
+// a BooleanExpression that can be used as a condition.
+func CastBool(expr Expression) boolCast {
+	return boolCast{cast{expr, BoolType()}}
+}
+
+type cast struct {
+	expr     Expression
+	dataType DataType
+}
+
+func (c cast) ToSQLExpr(p *Params) string {
+	p.checkType(c.dataType)
+	return fmt.Sprintf("CAST(%s AS %s)", p.Expr("expr", c.expr), c.dataType)
+}
+
+func (c cast) Relations() []string {
+	return c.expr.Relations()
+}
+
+type boolCast struct {
+	cast
+}
+
+func (c boolCast) ToSQLBoolean(p *Params) string {
+	return c.ToSQLExpr(p)
+}
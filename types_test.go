@@ -0,0 +1,155 @@
+package psql
+
+import "testing"
+
+func TestDataTypeString(t *testing.T) {
+	cases := []struct {
+		dataType DataType
+		str      string
+	}{
+		{TextType(), "text"},
+		{FloatType(), "double precision"},
+		{Numeric(10, 2), "numeric(10,2)"},
+		{Numeric(3, 5), "numeric(3,5)"},
+		{Numeric(5, -2), "numeric(5,-2)"},
+		{Varchar(255), "varchar(255)"},
+		{Char(2), "char(2)"},
+		{TimestampTZ(3), "timestamptz(3)"},
+		{ArrayOf(ArrayOf(IntType())), "integer[][]"},
+		{ArrayOf(Varchar(32)), "varchar(32)[]"},
+		{NamedType("public.mood"), `"public"."mood"`},
+		{ArrayOf(NamedType("mood")), `"mood"[]`},
+	}
+
+	for i, tc := range cases {
+		if str := tc.dataType.String(); str != tc.str {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.str, str)
+		}
+	}
+}
+
+func TestCastSQL(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		sql   string
+	}{
+		{
+			Select(Cast(TableColumn("products", "price"), Numeric(10, 2))),
+			`SELECT CAST("price" AS numeric(10,2)) FROM "products"`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "mood"), Cast(StringParam(), NamedType("public.mood"))),
+			),
+			`SELECT "name" FROM "users" WHERE ("mood" = CAST($1::text AS "public"."mood"))`,
+		},
+		{
+			Select(TableColumn("flags", "name")).Where(CastBool(TableColumn("flags", "value"))),
+			`SELECT "name" FROM "flags" WHERE CAST("value" AS boolean)`,
+		},
+		{
+			Select(TableColumn("products", "name")).Where(
+				GreaterThan(TableColumn("products", "price"), FreeParam(Numeric(10, 2))),
+			),
+			`SELECT "name" FROM "products" WHERE ("price" > $1::numeric(10,2))`,
+		},
+		{
+			Select(TableColumn("users", "name")).Where(
+				Eq(TableColumn("users", "tags"), FreeParam(ArrayOf(Varchar(32)))),
+			),
+			`SELECT "name" FROM "users" WHERE ("tags" = $1::varchar(32)[])`,
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+	}
+}
+
+func TestDataTypeErrors(t *testing.T) {
+	cases := []struct {
+		query SelectQuery
+		err   string
+	}{
+		{
+			Select(Cast(TableColumn("products", "price"), Numeric(0, 2))),
+			"psql: SELECT[0]: invalid precision or scale in type numeric(0,2)",
+		},
+		{
+			Select(Cast(TableColumn("products", "price"), Numeric(4, 1001))),
+			"psql: SELECT[0]: invalid precision or scale in type numeric(4,1001)",
+		},
+		{
+			Select(Cast(TableColumn("users", "name"), Varchar(0))),
+			"psql: SELECT[0]: invalid length in type varchar(0)",
+		},
+		{
+			Select(FreeParam(Timestamp(7))),
+			"psql: SELECT[0]: invalid precision in type timestamp(7)",
+		},
+		{
+			Select(Cast(TableColumn("users", "mood"), NamedType(""))),
+			"psql: SELECT[0]: user-defined type has an empty name",
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.query.Build()
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	cases := []struct {
+		name     string
+		dataType DataType
+		err      string
+	}{
+		{"uuid", UUIDType(), ""},
+		{"TEXT[]", ArrayOf(TextType()), ""},
+		{"varchar(255)", Varchar(255), ""},
+		{"character varying(8)[]", ArrayOf(Varchar(8)), ""},
+		{"numeric(10, 2)", Numeric(10, 2), ""},
+		{"numeric(10)", NumericType().withMods(10), ""},
+		{"double  precision", FloatType(), ""},
+		{"timestamp with time zone", TimestampTZType(), ""},
+		{"timestamp(3) with time zone", TimestampTZ(3), ""},
+		{"public.mood", NamedType("public.mood"), ""},
+		{"mood[][]", ArrayOf(ArrayOf(NamedType("mood"))), ""},
+		{"", DataType{}, `invalid type ""`},
+		{"not a type", DataType{}, `invalid type "not a type"`},
+		{"a.b.c", DataType{}, `invalid type "a.b.c"`},
+		{"varchar(", DataType{}, `invalid type "varchar("`},
+		{"varchar(n)", DataType{}, `invalid modifier "n" in type "varchar(n)"`},
+		{"text(5)", DataType{}, `too many modifiers in type "text(5)"`},
+		{"mood(1)", DataType{}, `too many modifiers in type "mood(1)"`},
+		{"numeric(1,2,3)", DataType{}, `too many modifiers in type "numeric(1,2,3)"`},
+		{"varchar(0)", DataType{}, "invalid length in type varchar(0)"},
+	}
+
+	for i, tc := range cases {
+		dataType, err := parseDataType(tc.name)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+		} else if dataType != tc.dataType {
+			t.Errorf("test case %d: expected %s, got %s", i+1, tc.dataType, dataType)
+		}
+	}
+}
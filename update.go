@@ -0,0 +1,111 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Update creates a new UpdateQuery that modifies rows of the table with the
+// given name. As with Table, the name may be schema-qualified.
+func Update(name string) UpdateQuery {
+	return UpdateQuery{table: parseTable(name)}
+}
+
+// An UpdateQuery represents an UPDATE statement.
+type UpdateQuery struct {
+	table table
+	set   []Assignment
+	from  []Relation
+	where whereClause
+}
+
+// An Assignment sets a column to a value, such as in the SET clause of an
+// UPDATE statement. If Value implements Expression, it is embedded in the
+// query as such; otherwise, it is passed to the database as a parameter.
+type Assignment struct {
+	Column string
+	Value  interface{}
+}
+
+// Set returns a copy of the UpdateQuery u with additional assignments in
+// its SET clause.
+func (u UpdateQuery) Set(assignments ...Assignment) UpdateQuery {
+	set := make([]Assignment, len(u.set), len(u.set)+len(assignments))
+	copy(set, u.set)
+	u.set = append(set, assignments...)
+	return u
+}
+
+// From returns a copy of the UpdateQuery u with a FROM clause listing
+// other relations whose columns can be used in the SET and WHERE clauses,
+// such as a VALUES list. This method overwrites any previous FROM clause.
+// The query's columns are then qualified with the names of their tables.
+func (u UpdateQuery) From(rels ...Relation) UpdateQuery {
+	u.from = rels
+	return u
+}
+
+// Where returns a copy of the UpdateQuery u with a WHERE clause containing
+// the BooleanExpressions provided. If a WHERE clause was already present,
+// this method will overwrite it.
+func (u UpdateQuery) Where(exprs ...BooleanExpression) UpdateQuery {
+	u.where = whereClause{exprs}
+	return u
+}
+
+// ToSQL returns a string containing the full SQL version of the UpdateQuery.
+func (u UpdateQuery) ToSQL() string {
+	return u.Compile().SQL
+}
+
+// Compile converts the UpdateQuery into a Statement. As with SelectQuery,
+// problems with the query are only reported by Build.
+func (u UpdateQuery) Compile() Statement {
+	p := newParams()
+	return p.statement(u.toSQL(p))
+}
+
+// Build is like Compile, but returns a BuildErrors describing every problem
+// found in the query, if any.
+func (u UpdateQuery) Build() (Statement, error) {
+	p := newParams()
+	sql := u.toSQL(p)
+
+	if err := p.err(); err != nil {
+		return Statement{}, err
+	}
+
+	return p.statement(sql), nil
+}
+
+func (u UpdateQuery) toSQL(p *Params) string {
+	if len(u.set) == 0 {
+		p.Errorf("an UPDATE query must set at least one column")
+	}
+
+	// The target table is in scope as well as those in the FROM clause.
+	defer p.scope(1 + len(u.from))()
+
+	sets := make([]string, len(u.set))
+	for i, a := range u.set {
+		expr, ok := a.Value.(Expression)
+		if !ok {
+			expr = boundParam{a.Value}
+		}
+		sets[i] = fmt.Sprintf("%s = %s", pq.QuoteIdentifier(a.Column), p.Expr(fmt.Sprintf("SET[%d]", i), expr))
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s", u.table, strings.Join(sets, ", "))
+
+	if from := (fromClause{rels: u.from}).ToSQLClause(p); from != "" {
+		sql = fmt.Sprintf("%s %s", sql, from)
+	}
+
+	if where := u.where.ToSQLClause(p); where != "" {
+		sql = fmt.Sprintf("%s %s", sql, where)
+	}
+
+	return sql
+}
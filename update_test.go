@@ -0,0 +1,63 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateQuerySQL(t *testing.T) {
+	v := TypedValues(
+		[]DataType{IntType(), TextType()},
+		[]interface{}{1, "Joe"},
+		[]interface{}{2, "Jane"},
+	).As("v", "id", "name")
+
+	cases := []struct {
+		query UpdateQuery
+
+		sql  string
+		args []interface{}
+	}{
+		{
+			Update("users").Set(
+				Assignment{"name", "Joe"},
+				Assignment{"visits", Plus(TableColumn("users", "visits"), IntLiteral(1))},
+			).Where(
+				Eq(TableColumn("users", "id"), IntParam()),
+			),
+			`UPDATE "users" SET "name" = $1, "visits" = ("visits" + 1) WHERE ("id" = $2::integer)`,
+			[]interface{}{"Joe", nil},
+		},
+		{
+			Update("users").Set(
+				Assignment{"name", v.Column("name")},
+			).From(
+				v,
+			).Where(
+				Eq(TableColumn("users", "id"), v.Column("id")),
+			),
+			`UPDATE "users" SET "name" = "v"."name" FROM (VALUES ($1::integer, $2::text), ($3::integer, $4::text)) AS "v" ("id", "name") WHERE ("users"."id" = "v"."id")`,
+			[]interface{}{1, "Joe", 2, "Jane"},
+		},
+	}
+
+	for i, tc := range cases {
+		st, err := tc.query.Build()
+		if err != nil {
+			t.Errorf("test case %d: unexpected error: %v", i+1, err)
+			continue
+		}
+
+		if st.SQL != tc.sql {
+			t.Errorf("test case %d: expected %q, got %q", i+1, tc.sql, st.SQL)
+		}
+
+		if !reflect.DeepEqual(st.Args, tc.args) {
+			t.Errorf("test case %d: expected %v, got %v", i+1, tc.args, st.Args)
+		}
+	}
+
+	if _, err := Update("users").Build(); err == nil {
+		t.Error("expected an error for an UPDATE without assignments, got nil")
+	}
+}
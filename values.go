@@ -0,0 +1,107 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Values returns a VALUES list, with one row for each slice of Expressions
+// in rows. All rows must have the same number of columns. To be used as a
+// Relation, the list must be given an alias and column names with As.
+func Values(rows ...[]Expression) valuesList {
+	return valuesList{rows: rows}
+}
+
+// TypedValues is like Values, but creates each row from a slice of Go
+// values, which are passed to the database as parameters cast to the
+// corresponding entry in types. There must be exactly one type for each
+// column.
+func TypedValues(types []DataType, rows ...[]interface{}) valuesList {
+	var err string
+	exprs := make([][]Expression, len(rows))
+	for i, row := range rows {
+		if err == "" {
+			switch {
+			case len(row) > len(types):
+				err = fmt.Sprintf("TypedValues has no type for column %d of row %d", len(types), i)
+			case len(row) < len(types):
+				err = fmt.Sprintf("TypedValues was given %d types, but row %d has %d columns", len(types), i, len(row))
+			}
+		}
+
+		exprs[i] = make([]Expression, len(row))
+		for j, value := range row {
+			if j < len(types) {
+				exprs[i][j] = literal{value, types[j]}
+			} else {
+				exprs[i][j] = boundParam{value}
+			}
+		}
+	}
+	return valuesList{rows: exprs, err: err}
+}
+
+type valuesList struct {
+	rows [][]Expression
+
+	// err, if not empty, is reported when the list is converted to SQL.
+	err string
+}
+
+// As returns a Relation representing the VALUES list under the given alias,
+// with columns named after columns.
+func (v valuesList) As(alias string, columns ...string) valuesTable {
+	return valuesTable{v, alias, columns}
+}
+
+func (v valuesList) toSQL(p *Params, width int) string {
+	if len(v.rows) == 0 {
+		p.Errorf("the VALUES list is empty")
+	}
+
+	if v.err != "" {
+		p.Errorf("%s", v.err)
+	}
+
+	rows := make([]string, len(v.rows))
+	for i, row := range v.rows {
+		if len(row) != width {
+			p.Errorf("row %d of the VALUES list has %d columns, expected %d", i, len(row), width)
+		}
+
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = p.Expr(fmt.Sprintf("VALUES[%d][%d]", i, j), cell)
+		}
+		rows[i] = fmt.Sprintf("(%s)", strings.Join(cells, ", "))
+	}
+
+	return fmt.Sprintf("VALUES %s", strings.Join(rows, ", "))
+}
+
+type valuesTable struct {
+	values  valuesList
+	alias   string
+	columns []string
+}
+
+// Column returns an Expression representing the column with the given
+// name in the VALUES list.
+func (v valuesTable) Column(name string) aliasColumn {
+	return aliasColumn{v.alias, name}
+}
+
+func (v valuesTable) ToSQLRelation(p *Params) string {
+	cols := make([]string, len(v.columns))
+	for i, col := range v.columns {
+		cols[i] = pq.QuoteIdentifier(col)
+	}
+
+	return fmt.Sprintf("(%s) AS %s (%s)",
+		v.values.toSQL(p, len(v.columns)),
+		pq.QuoteIdentifier(v.alias),
+		strings.Join(cols, ", "),
+	)
+}
@@ -0,0 +1,90 @@
+package psql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	v := TypedValues(
+		[]DataType{IntType(), TextType()},
+		[]interface{}{1, "Joe"},
+		[]interface{}{2, "Jane"},
+	).As("v", "id", "name")
+
+	query := Select(
+		TableColumn("users", "email"),
+		v.Column("name"),
+	).Join(
+		v, Eq(TableColumn("users", "id").Qualified(), v.Column("id")),
+	).Where(
+		IsNotNull(TableColumn("users", "email")),
+	)
+
+	st, err := query.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sql := `SELECT "users"."email", "v"."name" FROM "users" JOIN (VALUES ($1::integer, $2::text), ($3::integer, $4::text)) AS "v" ("id", "name") ON ("users"."id" = "v"."id") WHERE "users"."email" IS NOT NULL`
+	if st.SQL != sql {
+		t.Errorf("expected %q, got %q", sql, st.SQL)
+	}
+
+	want := []interface{}{1, "Joe", 2, "Jane"}
+	if !reflect.DeepEqual(st.Args, want) {
+		t.Errorf("expected %v, got %v", want, st.Args)
+	}
+
+	query = Select(
+		AllColumns("v"),
+	).From(
+		Values(
+			[]Expression{IntLiteral(1), Now()},
+			[]Expression{IntParam(), NullLiteral(TimestampTZType())},
+		).As("v", "n", "at"),
+	)
+
+	sql = `SELECT "v".* FROM (VALUES (1, now()), ($1::integer, NULL::timestamptz)) AS "v" ("n", "at")`
+	if got := query.ToSQL(); got != sql {
+		t.Errorf("expected %q, got %q", sql, got)
+	}
+}
+
+func TestValuesErrors(t *testing.T) {
+	cases := []Relation{
+		Values().As("v", "id"),
+		Values([]Expression{IntLiteral(1), IntLiteral(2)}).As("v", "id"),
+		TypedValues([]DataType{IntType()}, []interface{}{1}, []interface{}{2, 3}).As("v", "id"),
+	}
+
+	for i, rel := range cases {
+		if _, err := Select(Now()).From(rel).Build(); err == nil {
+			t.Errorf("test case %d: expected an error, got nil", i+1)
+		}
+	}
+}
+
+func TestTypedValuesTypeCount(t *testing.T) {
+	cases := []struct {
+		rel Relation
+		err string
+	}{
+		{
+			TypedValues([]DataType{IntType()}, []interface{}{1, "Joe"}).As("v", "id", "name"),
+			"psql: FROM[0]: TypedValues has no type for column 1 of row 0",
+		},
+		{
+			TypedValues([]DataType{IntType(), TextType()}, []interface{}{1, "Joe"}, []interface{}{2}).As("v", "id", "name"),
+			"psql: FROM[0]: TypedValues was given 2 types, but row 1 has 1 columns",
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := Select(Now()).From(tc.rel).Build()
+		if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+			t.Errorf("test case %d: expected error %q, got %v", i+1, tc.err, err)
+		}
+	}
+}